@@ -0,0 +1,151 @@
+package goql
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+)
+
+// CompiledDecoder is a read-only Decoder, which is safe for concurrent use.
+// The configuration is validated once when compiled, instead of on every
+// decode.
+type CompiledDecoder[T any] struct {
+	dec *Decoder[T]
+}
+
+// Compile validates the decoder, and returns a copy that can no longer be
+// modified. Changes to the decoder after compiling does not affect the
+// compiled decoder.
+func (d *Decoder[T]) Compile() (*CompiledDecoder[T], error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	dec := d.Clone()
+	dec.compiled = true
+
+	return &CompiledDecoder[T]{dec: dec}, nil
+}
+
+// Clone returns a deep copy of the decoder, e.g. to derive the per-route
+// variants from a base decoder. The tags, parsers and options are not shared.
+func (d *Decoder[T]) Clone() *Decoder[T] {
+	c := *d
+	c.compiled = false
+
+	c.tags = make(map[string]*Tag, len(d.tags))
+	for name, tag := range d.tags {
+		c.tags[name] = tag.clone()
+	}
+
+	c.parsers = make(map[string]ParserFn, len(d.parsers))
+	for name, fn := range d.parsers {
+		c.parsers[name] = fn
+	}
+
+	c.typeParsers = make(map[reflect.Type]ParserFn, len(d.typeParsers))
+	for t, fn := range d.typeParsers {
+		c.typeParsers[t] = fn
+	}
+
+	c.ops = make(map[string]Op, len(d.ops))
+	for field, ops := range d.ops {
+		c.ops[field] = ops
+	}
+
+	c.columns = make(map[string]string, len(d.columns))
+	for field, column := range d.columns {
+		c.columns[field] = column
+	}
+
+	c.scope = append([]FieldSet(nil), d.scope...)
+	c.defaultSort = append([]Order(nil), d.defaultSort...)
+	c.reserved = append([]string(nil), d.reserved...)
+	c.ignoreKeys = append([]string(nil), d.ignoreKeys...)
+
+	return &c
+}
+
+// Clone returns a modifiable copy of the compiled decoder.
+func (c *CompiledDecoder[T]) Clone() *Decoder[T] {
+	return c.dec.Clone()
+}
+
+// Decode decodes the url.Values. The FieldSet.Tag of the filter is a copy, so
+// that the compiled decoder cannot be modified through it.
+func (c *CompiledDecoder[T]) Decode(u url.Values) (*Filter, error) {
+	return detach(c.dec.Decode(u))
+}
+
+func (c *CompiledDecoder[T]) DecodeContext(ctx context.Context, u url.Values) (*Filter, error) {
+	return detach(c.dec.DecodeContext(ctx, u))
+}
+
+func (c *CompiledDecoder[T]) DecodeString(rawQuery string) (*Filter, error) {
+	return detach(c.dec.DecodeString(rawQuery))
+}
+
+func (c *CompiledDecoder[T]) DecodeStringContext(ctx context.Context, rawQuery string) (*Filter, error) {
+	return detach(c.dec.DecodeStringContext(ctx, rawQuery))
+}
+
+// Scope returns the field sets that are merged into the filters decoded with
+// the context.
+func (c *CompiledDecoder[T]) Scope(ctx context.Context) ([]FieldSet, error) {
+	sets, err := c.dec.Scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return detachSets(sets, make(map[*Tag]*Tag)), nil
+}
+
+// Cursor returns the cursor of the row for the sort of the filter.
+func (c *CompiledDecoder[T]) Cursor(f *Filter, row T) (string, error) {
+	return c.dec.Cursor(f, row)
+}
+
+// Encoder returns an Encoder with the same query string names as the decoder.
+func (c *CompiledDecoder[T]) Encoder() *Encoder {
+	return c.dec.Encoder()
+}
+
+// detach replaces the tags of the filter with copies, which are shared by the
+// field sets of the same field.
+func detach(f *Filter, err error) (*Filter, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	clones := make(map[*Tag]*Tag)
+	f.And = detachSets(f.And, clones)
+	f.Or = detachSets(f.Or, clones)
+	f.Scope = detachSets(f.Scope, clones)
+
+	return f, nil
+}
+
+func detachSets(sets []FieldSet, clones map[*Tag]*Tag) []FieldSet {
+	if sets == nil {
+		return nil
+	}
+
+	res := make([]FieldSet, len(sets))
+	for i, fs := range sets {
+		if fs.Tag != nil {
+			tag, ok := clones[fs.Tag]
+			if !ok {
+				tag = fs.Tag.clone()
+				clones[fs.Tag] = tag
+			}
+
+			fs.Tag = tag
+		}
+
+		fs.And = detachSets(fs.And, clones)
+		fs.Or = detachSets(fs.Or, clones)
+		res[i] = fs
+	}
+
+	return res
+}
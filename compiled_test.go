@@ -0,0 +1,120 @@
+package goql_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+)
+
+func TestCompiledDecoder(t *testing.T) {
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	cdec, err := dec.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes after compiling does not affect the compiled decoder.
+	dec.SetOps("age", goql.OpEq)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			f, err := cdec.DecodeString("name.eq=john&age.gt=10")
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			if exp, got := 2, len(f.And); exp != got {
+				t.Errorf("expected %v, got %v", exp, got)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	_, err = dec.DecodeString("age.gt=10")
+	if !errors.Is(err, goql.ErrUnknownOperator) {
+		t.Fatalf("expected %v, got %v", goql.ErrUnknownOperator, err)
+	}
+}
+
+func TestCompiledDecoderClone(t *testing.T) {
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	base, err := goql.NewDecoder[User]().Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The clone does not modify the base decoder.
+	admin := base.Clone().SetOps("age", goql.OpEq).SetColumn("name", "u.name")
+
+	f, err := admin.DecodeString("name.eq=john")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, got := "u.name", f.And[0].Column; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	f, err = base.DecodeString("name.eq=john&age.gt=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, got := "name", f.And[1].Column; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	type User struct {
+		Email string `q:",type:email"`
+	}
+
+	_, err := goql.NewDecoder[User]().Compile()
+	if !errors.Is(err, goql.ErrUnknownParser) {
+		t.Fatalf("expected %v, got %v", goql.ErrUnknownParser, err)
+	}
+}
+
+func TestCompiledDecoderTags(t *testing.T) {
+	type User struct {
+		Age int
+	}
+
+	cdec, err := goql.NewDecoder[User]().Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := cdec.DecodeString("age.gt=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tags of the filter are copies.
+	f.And[0].Tag.Ops = goql.OpEq
+
+	if _, err := cdec.DecodeString("age.gt=10"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,306 @@
+package goql
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+var (
+	ErrInvalidCursor = errors.New("goql: invalid cursor")
+)
+
+// parseCursor adds the keyset pagination predicate for the `after` or `before`
+// cursor to the filter. The cursor holds the sort-key values of the last row
+// of the previous page.
+func (d *Decoder[T]) parseCursor(u url.Values, f *Filter) error {
+	after, before := u.Get(d.queryAfter), u.Get(d.queryBefore)
+	if after == "" && before == "" {
+		return nil
+	}
+
+	if after != "" && before != "" {
+		return &DecodeError{
+			Key:   d.queryBefore,
+			Value: before,
+			Err:   fmt.Errorf("%w: both %s and %s are set", ErrInvalidCursor, d.queryAfter, d.queryBefore),
+		}
+	}
+
+	key, cursor := d.queryAfter, after
+	if before != "" {
+		key, cursor = d.queryBefore, before
+	}
+
+	decodeErr := func(field string, err error) *DecodeError {
+		return &DecodeError{
+			Key:   key,
+			Field: field,
+			Value: cursor,
+			Err:   err,
+		}
+	}
+
+	if len(f.Sort) == 0 {
+		return decodeErr("", fmt.Errorf("%w: cursor requires %s", ErrInvalidCursor, d.querySort))
+	}
+
+	if before != "" {
+		// Paginating backwards fetches the rows nearest to the cursor first,
+		// and the rows needs to be reversed after fetching.
+		f.Sort = reverseOrders(f.Sort)
+		f.Reverse = true
+	}
+
+	values, err := decodeCursor(cursor)
+	if err != nil {
+		return decodeErr("", err)
+	}
+
+	if len(values) != len(f.Sort) {
+		return decodeErr("", fmt.Errorf("%w: cursor does not match %s", ErrInvalidCursor, d.querySort))
+	}
+
+	keys := make([]FieldSet, len(f.Sort))
+	for i, o := range f.Sort {
+		raw, ok := values[o.Field]
+		if !ok {
+			return decodeErr(o.Field, fmt.Errorf("%w: missing %s", ErrInvalidCursor, o.Field))
+		}
+
+		tag := d.tags[o.Field]
+		fs := FieldSet{
+			Tag:    tag,
+			Name:   o.Field,
+			Column: tag.Column,
+		}
+
+		if raw != nil {
+			parser, ok := d.parser(tag.Type, d.goType(tag, nil))
+			if !ok {
+				return decodeErr(o.Field, fmt.Errorf("%w: %s", ErrUnknownParser, tag.Type.Name))
+			}
+
+			v, err := parser(*raw)
+			if err != nil {
+				return decodeErr(o.Field, fmt.Errorf("%w: %s", ErrInvalidCursor, err))
+			}
+
+			fs.Value = v
+			fs.Values = []string{*raw}
+		}
+
+		keys[i] = fs
+	}
+
+	f.And = mergeAnd(f, keysetPredicate(f.Sort, keys))
+
+	return nil
+}
+
+// Cursor returns the cursor for the row, which is usually the last row of the
+// page. The cursor is passed to the `after` query string to fetch the next
+// page.
+func (d *Decoder[T]) Cursor(f *Filter, row T) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(row))
+
+	values := make(map[string]*string)
+	for _, o := range f.Sort {
+		tag, ok := d.tags[o.Field]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownField, o.Field)
+		}
+
+		// A nil embedded pointer has no value, and is treated as null.
+		fv, err := rv.FieldByIndexErr(tag.Index)
+		if err != nil {
+			values[o.Field] = nil
+
+			continue
+		}
+
+		values[o.Field] = cursorValue(fv.Interface())
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(cursor string) (map[string]*string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	var values map[string]*string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	return values, nil
+}
+
+// cursorValue returns nil for null values.
+func cursorValue(v any) *string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil || val == nil {
+			return nil
+		}
+	}
+
+	s := FormatValue(v)
+
+	return &s
+}
+
+// keysetPredicate returns the predicate for the rows after the keys, e.g. for
+// `sort_by=age&sort_by=id`:
+//
+//	age > $1 OR (age = $1 AND id > $2)
+//
+// Null keys are compared with `is null` and `is not null`, depending on the
+// sort option.
+func keysetPredicate(orders []Order, keys []FieldSet) FieldSet {
+	ors := make([]FieldSet, 0, len(keys))
+
+	for i, key := range keys {
+		after, ok := afterKey(orders[i], key)
+		if !ok {
+			continue
+		}
+
+		ands := make([]FieldSet, 0, i+1)
+		for _, prev := range keys[:i] {
+			ands = append(ands, equalKey(prev))
+		}
+
+		ands = append(ands, after)
+		ors = append(ors, conj(OpAnd, ands))
+	}
+
+	return conj(OpOr, ors)
+}
+
+// afterKey returns the predicate for the rows after the key. It returns false
+// if there are no rows after the key, e.g. null keys sorted last.
+func afterKey(o Order, key FieldSet) (FieldSet, bool) {
+	nullsLast := o.Option == SortOptionNullsLast
+
+	if key.Value == nil {
+		if nullsLast {
+			return FieldSet{}, false
+		}
+
+		return nullKey(key, OpIsNot), true
+	}
+
+	after := key
+	after.Op = OpGt
+	if o.Direction == SortDirectionDescending {
+		after.Op = OpLt
+	}
+
+	// Fields that are not nullable do not need to compare nulls.
+	if !nullsLast || key.Tag == nil || !key.Tag.Type.Null {
+		return after, true
+	}
+
+	return conj(OpOr, []FieldSet{after, nullKey(key, OpIs)}), true
+}
+
+func equalKey(key FieldSet) FieldSet {
+	if key.Value == nil {
+		return nullKey(key, OpIs)
+	}
+
+	key.Op = OpEq
+
+	return key
+}
+
+func nullKey(key FieldSet, op Op) FieldSet {
+	key.Op = op
+	key.Value = nil
+	key.Values = []string{"null"}
+
+	return key
+}
+
+// conj returns the conjunction of the field sets, or the field set itself if
+// there is only one.
+func conj(op Op, sets []FieldSet) FieldSet {
+	if len(sets) == 1 {
+		return sets[0]
+	}
+
+	fs := FieldSet{
+		Name: op.String(),
+		Op:   op,
+	}
+
+	if op == OpAnd {
+		fs.And = sets
+	} else {
+		fs.Or = sets
+	}
+
+	return fs
+}
+
+// mergeAnd returns the `And` field sets of the filter with the given field
+// sets. Since the top-level `Or` is joined with `or`, e.g. `a AND b OR c`, the
+// filter is wrapped as `x AND (a AND b OR c)` so that the rows that matches
+// `c` are still filtered by `x`.
+func mergeAnd(f *Filter, sets ...FieldSet) []FieldSet {
+	res := make([]FieldSet, 0, len(sets)+len(f.And))
+	res = append(res, sets...)
+
+	if len(f.Or) == 0 {
+		return append(res, f.And...)
+	}
+
+	ors := make([]FieldSet, 0, len(f.Or)+1)
+	if len(f.And) > 0 {
+		ors = append(ors, conj(OpAnd, f.And))
+	}
+
+	ors = append(ors, f.Or...)
+
+	f.Or = nil
+
+	return append(res, conj(OpOr, ors))
+}
+
+func reverseOrders(orders []Order) []Order {
+	res := make([]Order, len(orders))
+	for i, o := range orders {
+		o.Direction = reverseDirection[o.Direction]
+		o.Option = reverseOption[o.Option]
+		res[i] = o
+	}
+
+	return res
+}
+
+var reverseDirection = map[SortDirection]SortDirection{
+	SortDirectionAscending:  SortDirectionDescending,
+	SortDirectionDescending: SortDirectionAscending,
+}
+
+var reverseOption = map[SortOption]SortOption{
+	SortOptionNullsFirst: SortOptionNullsLast,
+	SortOptionNullsLast:  SortOptionNullsFirst,
+}
@@ -0,0 +1,189 @@
+package goql_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCursor(t *testing.T) {
+	type User struct {
+		ID   int    `sort:"true"`
+		Age  *int   `sort:"true"`
+		Name string `sort:"true"`
+	}
+
+	age := 17
+
+	tests := []struct {
+		name  string
+		sort  []string
+		key   string
+		row   User
+		where string
+		args  []any
+	}{
+		{
+			name:  "single key",
+			sort:  []string{"id"},
+			key:   "after",
+			row:   User{ID: 10},
+			where: "WHERE id > $1 ORDER BY id ASC NULLS LAST",
+			args:  []any{10},
+		},
+		{
+			name:  "tie-breaker",
+			sort:  []string{"name.desc", "id"},
+			key:   "after",
+			row:   User{ID: 10, Name: "john"},
+			where: "WHERE (name < $1 OR (name = $2 AND id > $3)) ORDER BY name DESC NULLS FIRST, id ASC NULLS LAST",
+			args:  []any{"john", "john", 10},
+		},
+		{
+			name:  "nulls last",
+			sort:  []string{"age", "id"},
+			key:   "after",
+			row:   User{ID: 10, Age: &age},
+			where: "WHERE ((age > $1 OR age is null) OR (age = $2 AND id > $3)) ORDER BY age ASC NULLS LAST, id ASC NULLS LAST",
+			args:  []any{&age, &age, 10},
+		},
+		{
+			name:  "null key sorted last",
+			sort:  []string{"age", "id"},
+			key:   "after",
+			row:   User{ID: 10},
+			where: "WHERE (age is null AND id > $1) ORDER BY age ASC NULLS LAST, id ASC NULLS LAST",
+			args:  []any{10},
+		},
+		{
+			name:  "null key sorted first",
+			sort:  []string{"age.asc.nullsfirst", "id"},
+			key:   "after",
+			row:   User{ID: 10},
+			where: "WHERE (age is not null OR (age is null AND id > $1)) ORDER BY age ASC NULLS FIRST, id ASC NULLS LAST",
+			args:  []any{10},
+		},
+		{
+			name:  "before",
+			sort:  []string{"age", "id"},
+			key:   "before",
+			row:   User{ID: 10},
+			where: "WHERE (age is not null OR (age is null AND id < $1)) ORDER BY age DESC NULLS FIRST, id DESC NULLS FIRST",
+			args:  []any{10},
+		},
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := url.Values{"sort_by": tt.sort}
+
+			f, err := dec.Decode(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cursor, err := dec.Cursor(f, tt.row)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v.Set(tt.key, cursor)
+
+			f, err = dec.Decode(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.key == "before", f.Reverse; exp != got {
+				t.Fatalf("expected %v, got %v", exp, got)
+			}
+
+			where, args, err := goql.BuildSQL(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.where, where; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff(tt.args, args); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCursorWrapsOr(t *testing.T) {
+	type User struct {
+		ID   int `sort:"true"`
+		Name string
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	cursor, err := dec.Cursor(&goql.Filter{Sort: []goql.Order{{Field: "id"}}}, User{ID: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := make(url.Values)
+	v.Set("name.eq", "alice")
+	v.Set("or", "name.eq:bob")
+	v.Set("sort_by", "id")
+	v.Set("after", cursor)
+
+	f, err := dec.Decode(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	where, _, err := goql.BuildSQL(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, got := "WHERE id > $1 AND (name = $2 OR name = $3) ORDER BY id ASC NULLS LAST", where; exp != got {
+		t.Fatalf("expected %q, got %q", exp, got)
+	}
+}
+
+func TestCursorInvalid(t *testing.T) {
+	type User struct {
+		ID   int `sort:"true"`
+		Name string
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"not base64", "sort_by=id&after=!"},
+		{"not json", "sort_by=id&after=aGVsbG8"},
+		{"missing sort", "after=eyJpZCI6IjEwIn0"},
+		{"mismatch sort", "sort_by=id&after=eyJuYW1lIjoiMTAifQ"},
+		{"bad value", "sort_by=id&after=eyJpZCI6ImEifQ"},
+		{"after and before", "sort_by=id&after=eyJpZCI6IjEwIn0&before=eyJpZCI6IjEwIn0"},
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = dec.Decode(v)
+			if !errors.Is(err, goql.ErrInvalidCursor) {
+				t.Fatalf("expected %v, got %v", goql.ErrInvalidCursor, err)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package goql
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
+	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -18,6 +21,8 @@ const (
 	QuerySort   = "sort_by"
 	QueryLimit  = "limit"
 	QueryOffset = "offset"
+	QueryAfter  = "after"
+	QueryBefore = "before"
 	QueryAnd    = "and"
 	QueryOr     = "or"
 
@@ -34,6 +39,7 @@ var (
 	ErrInvalidConjunction = errors.New("goql: invalid conjunction")
 	ErrBadValue           = errors.New("goql: bad value")
 	ErrTooManyValues      = errors.New("goql: too many values")
+	ErrIgnoredKey         = errors.New("goql: ignored key")
 )
 
 type Filter struct {
@@ -42,249 +48,559 @@ type Filter struct {
 	Or     []FieldSet
 	Limit  *int
 	Offset *int
+
+	// Reverse is true when paginating backwards with the `before` cursor. The
+	// sort is reversed, so the rows needs to be reversed after fetching.
+	Reverse bool
+
+	// Scope is the scope that is merged into the `And`, see
+	// Decoder.SetScope.
+	Scope []FieldSet
+
+	// Warnings are the query string keys that are ignored, see
+	// Decoder.SetIgnoreKeys and Decoder.SetIgnoreUnknown.
+	Warnings DecodeErrors
 }
 
 type FieldSet struct {
 	Tag    *Tag
 	Name   string
+	Column string
 	Value  any
 	Values []string
 	Op     Op
 
+	// Negated is true when the field set is negated with `not`, e.g.
+	// `age.not.gt=10` or `and=not.or.(age.gt:10,age.lt:20)`.
+	Negated bool
+
+	// Len is true when the cardinality of the array is compared instead,
+	// e.g. `tags.len.gt=3`. The value is an int.
+	Len bool
+
+	// Quantifier is the quantifier of the array elements, which is either
+	// OpAny or OpAll, e.g. `scores.anygt=50`. The value is a single element.
+	Quantifier Op
+
+	// Path is the path within the JSON field, e.g. `attrs.color.eq=red` or
+	// `attrs->color.eq=red` has the path `[color]`.
+	Path []string
+
 	Or  []FieldSet
 	And []FieldSet
 }
 
 func (f FieldSet) String() string {
+	if f.Negated {
+		return fmt.Sprintf("%v %v %v %#v", f.Name, OpNot, f.Op, f.Value)
+	}
+
 	return fmt.Sprintf("%v %v %#v", f.Name, f.Op, f.Value)
 }
 
 type Decoder[T any] struct {
-	tags        map[string]*Tag
-	parsers     map[string]ParserFn
-	sortTag     string
-	filterTag   string
-	limitMin    int
-	limitMax    int
-	querySort   string
-	queryLimit  string
-	queryOffset string
+	config
 }
 
 func NewDecoder[T any]() *Decoder[T] {
+	d, err := newDecoder[T]()
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// NewDecoderE returns the decoder configured with the options. Unlike
+// NewDecoder and the Set* methods, it returns the errors instead of panicking,
+// and the decoder is validated, e.g. ErrUnknownParser for the fields without
+// parsers. The options are applied in order.
+func NewDecoderE[T any](opts ...Option) (*Decoder[T], error) {
+	d, err := newDecoder[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		if err := opt(&d.config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func newDecoder[T any]() (*Decoder[T], error) {
 	var t T
 
 	parsers := NewParsers()
 	tags, err := ParseStruct(t, TagFilter, TagSort)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &Decoder[T]{
-		tags:        tags,
-		parsers:     parsers,
-		sortTag:     TagSort,
-		filterTag:   TagFilter,
-		limitMin:    LimitMin,
-		limitMax:    LimitMax,
-		querySort:   QuerySort,
-		queryLimit:  QueryLimit,
-		queryOffset: QueryOffset,
-	}
+		config: config{
+			value:       t,
+			tags:        tags,
+			types:       fieldTypes(t, tags),
+			parsers:     parsers,
+			sortTag:     TagSort,
+			filterTag:   TagFilter,
+			limitMin:    LimitMin,
+			limitMax:    LimitMax,
+			querySort:   QuerySort,
+			queryLimit:  QueryLimit,
+			queryOffset: QueryOffset,
+			queryAfter:  QueryAfter,
+			queryBefore: QueryBefore,
+			dialect:     Postgres{},
+		},
+	}, nil
 }
 
-// Validate checks if the parser exists for all the inferred types. This is
-// called internally before decode is called.
+// Validate checks if the parser exists for all the inferred types, and if the
+// fields of the default sort are sortable. This is called internally before
+// decode is called.
 func (d *Decoder[T]) Validate() error {
+	for _, o := range d.defaultSort {
+		if tag, ok := d.tags[o.Field]; !ok || !tag.Sort {
+			return fmt.Errorf("%w: %s", ErrUnsortableField, o.Field)
+		}
+	}
+
 	for _, tag := range d.tags {
-		if _, ok := d.parsers[tag.Type.Name]; !ok {
+		if _, ok := d.parser(tag.Type, d.goType(tag, nil)); !ok {
 			return fmt.Errorf("%w: missing parser for type %q", ErrUnknownParser, tag.Type.Name)
 		}
+
+		for path, typ := range tag.Paths {
+			if _, ok := d.parser(typ, d.goType(tag, strings.Split(path, "."))); !ok {
+				return fmt.Errorf("%w: missing parser for type %q of path %q", ErrUnknownParser, typ.Name, path)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (d *Decoder[T]) SetFilterTag(filterTag string) *Decoder[T] {
-	if filterTag == "" {
-		panic("goql: filter tag cannot be empty")
+// parser returns the parser for the type. The values of map fields are parsed
+// as JSON.
+func (d *Decoder[T]) parser(t Type, rt reflect.Type) (ParserFn, bool) {
+	return findParser(&d.config, t, rt)
+}
+
+// goType returns the Go type of the field, or of the JSON path, which is only
+// known for the types that are inferred from the struct fields.
+func (d *Decoder[T]) goType(tag *Tag, path []string) reflect.Type {
+	if len(path) == 0 {
+		return d.types[tag.Name]
 	}
 
-	var t T
-	tags, err := ParseStruct(t, filterTag, d.sortTag)
+	// The explicit types of the paths are aliases.
+	if p := strings.Join(path, "."); p != "*" {
+		if _, ok := tag.Paths[p]; ok {
+			return nil
+		}
+	}
 
-	if err != nil {
+	return d.types[tag.Name+".*"]
+}
+
+func (d *Decoder[T]) SetFilterTag(filterTag string) *Decoder[T] {
+	if err := d.setFilterTag(filterTag); err != nil {
 		panic(err)
 	}
 
-	d.filterTag = filterTag
-	d.tags = tags
-
 	return d
 }
 
 func (d *Decoder[T]) SetSortTag(sortTag string) *Decoder[T] {
-	if sortTag == "" {
-		panic("goql: sort tag cannot be empty")
-	}
-
-	var t T
-	tags, err := ParseStruct(t, d.filterTag, sortTag)
-	if err != nil {
+	if err := d.setSortTag(sortTag); err != nil {
 		panic(err)
 	}
 
-	d.sortTag = sortTag
-	d.tags = tags
-
 	return d
 }
 
 func (d *Decoder[T]) SetLimitRange(min, max int) *Decoder[T] {
-	if min == 0 || max == 0 {
-		panic("goql: limit and offset cannot be 0")
+	if err := d.setLimitRange(min, max); err != nil {
+		panic(err)
 	}
 
-	d.limitMin = min
-	d.limitMax = max
-
 	return d
 }
 
 func (d *Decoder[T]) SetParsers(parsers map[string]ParserFn) *Decoder[T] {
-	if len(parsers) == 0 {
-		panic("goql: no parsers specified")
+	if err := d.setParsers(parsers); err != nil {
+		panic(err)
 	}
 
-	d.parsers = parsers
-
 	return d
 }
 
 func (d *Decoder[T]) SetParser(name string, parserFn ParserFn) *Decoder[T] {
-	d.parsers[name] = parserFn
+	if err := d.setParser(name, parserFn); err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// RegisterParser registers the parser for the Go type V, which also applies to
+// the fields of type *V, []V and []*V. Unlike SetParser, the parser does not
+// depend on the name of the type, and it takes precedence over the parsers
+// that are registered by name.
+func RegisterParser[V, T any](d *Decoder[T], fn func(string) (V, error)) *Decoder[T] {
+	if err := WithTypeParser(fn)(&d.config); err != nil {
+		panic(err)
+	}
 
 	return d
 }
 
 func (d *Decoder[T]) SetOps(field string, ops Op) *Decoder[T] {
-	if field == "" {
-		panic("goql: set ops field cannot be empty")
+	if err := d.setOps(field, ops); err != nil {
+		panic(err)
 	}
 
-	if _, ok := d.tags[field]; !ok {
-		panic(fmt.Errorf("%w: %q", ErrUnknownField, field))
+	return d
+}
+
+// SetColumn sets the SQL column (or expression) for the field, while keeping
+// the public query string name unchanged.
+func (d *Decoder[T]) SetColumn(field, column string) *Decoder[T] {
+	if err := d.setColumn(field, column); err != nil {
+		panic(err)
 	}
 
-	if !ops.Valid() {
-		panic(fmt.Errorf("%w: %q", ErrInvalidOp, field))
+	return d
+}
+
+// SetDialect restricts the ops to those that can be rendered by the dialect.
+// Ops that are not supported fails with ErrUnsupportedOp when decoding.
+func (d *Decoder[T]) SetDialect(dialect Dialect) *Decoder[T] {
+	if err := d.setDialect(dialect); err != nil {
+		panic(err)
 	}
 
-	d.tags[field].Ops = ops
+	return d
+}
+
+// SetReservedKeys adds the query string keys that are not fields, e.g. `page`
+// or `q`, which are skipped when decoding.
+func (d *Decoder[T]) SetReservedKeys(keys ...string) *Decoder[T] {
+	if err := d.setReservedKeys(keys...); err != nil {
+		panic(err)
+	}
 
 	return d
 }
 
-func (d *Decoder[T]) SetQuerySortName(name string) *Decoder[T] {
-	if name == "" {
-		panic("goql: query sort name cannot be empty")
+// SetIgnoreKeys ignores the query string keys, e.g. `_t` or `utm_*`. A key
+// ending with `*` matches the prefix. The ignored keys are returned in
+// Filter.Warnings with ErrIgnoredKey.
+func (d *Decoder[T]) SetIgnoreKeys(keys ...string) *Decoder[T] {
+	if err := d.setIgnoreKeys(keys...); err != nil {
+		panic(err)
 	}
 
-	d.querySort = name
+	return d
+}
+
+// SetIgnoreUnknown ignores the query string keys that are not declared
+// fields, instead of failing with ErrUnknownField. The ignored keys are
+// returned in Filter.Warnings.
+func (d *Decoder[T]) SetIgnoreUnknown(ignore bool) *Decoder[T] {
+	d.ignoreUnknown = ignore
 
 	return d
 }
 
-func (d *Decoder[T]) SetQueryLimitName(name string) *Decoder[T] {
-	if name == "" {
-		panic("goql: query limit name cannot be empty")
+func (d *Decoder[T]) SetQuerySortName(name string) *Decoder[T] {
+	if err := d.setQuerySortName(name); err != nil {
+		panic(err)
 	}
 
-	d.queryLimit = name
+	return d
+}
+
+func (d *Decoder[T]) SetQueryLimitName(name string) *Decoder[T] {
+	if err := d.setQueryLimitName(name); err != nil {
+		panic(err)
+	}
 
 	return d
 }
 
 func (d *Decoder[T]) SetQueryOffsetName(name string) *Decoder[T] {
-	if name == "" {
-		panic("goql: query offset name cannot be empty")
+	if err := d.setQueryOffsetName(name); err != nil {
+		panic(err)
 	}
 
-	d.queryOffset = name
+	return d
+}
+
+func (d *Decoder[T]) SetQueryAfterName(name string) *Decoder[T] {
+	if err := d.setQueryAfterName(name); err != nil {
+		panic(err)
+	}
 
 	return d
 }
 
-func (d *Decoder[T]) Decode(u url.Values) (*Filter, error) {
-	if err := d.Validate(); err != nil {
-		return nil, err
+func (d *Decoder[T]) SetQueryBeforeName(name string) *Decoder[T] {
+	if err := d.setQueryBeforeName(name); err != nil {
+		panic(err)
 	}
 
-	limit, offset, err := d.parseLimit(u)
+	return d
+}
+
+// SetCollectErrors continues decoding after the first error, and returns all
+// the errors as DecodeErrors.
+func (d *Decoder[T]) SetCollectErrors(collect bool) *Decoder[T] {
+	d.collectErrors = collect
+
+	return d
+}
+
+func (d *Decoder[T]) Decode(u url.Values) (*Filter, error) {
+	return d.DecodeContext(context.Background(), u)
+}
+
+// DecodeContext decodes the url.Values, and passes the context to the
+// ScopeFunc.
+func (d *Decoder[T]) DecodeContext(ctx context.Context, u url.Values) (*Filter, error) {
+	return d.decode(ctx, u, func() paramOrder {
+		return valuesOrder(u)
+	})
+}
+
+// DecodeString decodes the raw query string. Unlike Decode, the errors
+// collected with SetCollectErrors are ordered by the parameters in the raw
+// query string.
+func (d *Decoder[T]) DecodeString(rawQuery string) (*Filter, error) {
+	return d.DecodeStringContext(context.Background(), rawQuery)
+}
+
+// DecodeStringContext decodes the raw query string, and passes the context to
+// the ScopeFunc.
+func (d *Decoder[T]) DecodeStringContext(ctx context.Context, rawQuery string) (*Filter, error) {
+	u, err := url.ParseQuery(rawQuery)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
 	}
 
-	ands, ors, err := d.parseFilter(u)
-	if err != nil {
-		return nil, err
+	return d.decode(ctx, u, func() paramOrder {
+		return rawQueryOrder(rawQuery)
+	})
+}
+
+// decode decodes the url.Values. The order of the parameters is only computed
+// to sort the warnings and the collected errors.
+func (d *Decoder[T]) decode(ctx context.Context, u url.Values, order func() paramOrder) (*Filter, error) {
+	if !d.compiled {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
-	sorts, err := d.parseSort(u)
-	if err != nil {
+	var errs DecodeErrors
+
+	u, warns := d.parseIgnored(u)
+
+	limit, offset, lerrs := d.parseLimit(u)
+	errs = append(errs, lerrs...)
+
+	if len(errs) > 0 && !d.collectErrors {
+		return nil, errs[0]
+	}
+
+	ands, ors, ferrs, fwarns := d.parseFilter(u)
+	errs = append(errs, ferrs...)
+	warns = append(warns, fwarns...)
+
+	if len(errs) > 0 && !d.collectErrors {
+		return nil, errs[0]
+	}
+
+	sorts, serrs := d.parseSort(u)
+	errs = append(errs, serrs...)
+
+	if len(errs) > 0 && !d.collectErrors {
+		return nil, errs[0]
+	}
+
+	if len(warns) > 0 {
+		order().sort(warns)
+	}
+
+	f := &Filter{
+		Sort:     sorts,
+		And:      ands,
+		Or:       ors,
+		Limit:    limit,
+		Offset:   offset,
+		Warnings: warns,
+	}
+
+	if len(errs) == 0 {
+		if err := d.parseCursor(u, f); err != nil {
+			errs = append(errs, decodeError(err))
+		}
+	}
+
+	if len(errs) > 0 && !d.collectErrors {
+		return nil, errs[0]
+	}
+
+	if len(errs) > 0 {
+		order().sort(errs)
+
+		return nil, errs
+	}
+
+	// The scope is merged last, so that it also wraps the cursor.
+	if err := d.applyScope(ctx, f); err != nil {
 		return nil, err
 	}
 
-	return &Filter{
-		Sort:   sorts,
-		And:    ands,
-		Or:     ors,
-		Limit:  limit,
-		Offset: offset,
-	}, nil
+	return f, nil
 }
 
-func (d *Decoder[T]) parseLimit(u url.Values) (limit, offset *int, err error) {
+func (d *Decoder[T]) parseLimit(u url.Values) (limit, offset *int, errs DecodeErrors) {
 	if v, ok := u[d.queryLimit]; ok && len(v) > 0 {
-		var n int
-		n, err = strconv.Atoi(v[0])
+		n, err := strconv.Atoi(v[0])
 		if err != nil {
-			return
-		}
+			errs = append(errs, &DecodeError{
+				Key:   d.queryLimit,
+				Value: v[0],
+				Err:   fmt.Errorf("%w: %s", ErrBadValue, err),
+			})
+		} else {
+			if n < d.limitMin {
+				n = d.limitMin
+			}
 
-		if n < d.limitMin {
-			n = d.limitMin
-		}
+			if n > d.limitMax {
+				n = d.limitMax
+			}
 
-		if n > d.limitMax {
-			n = d.limitMax
+			limit = &n
 		}
-
-		limit = &n
 	}
 
 	if v, ok := u[d.queryOffset]; ok && len(v) > 0 {
-		var n int
-		n, err = strconv.Atoi(v[0])
+		n, err := strconv.Atoi(v[0])
 		if err != nil {
-			return
-		}
+			errs = append(errs, &DecodeError{
+				Key:   d.queryOffset,
+				Value: v[0],
+				Err:   fmt.Errorf("%w: %s", ErrBadValue, err),
+			})
+		} else {
+			if n < 0 {
+				n = 0
+			}
 
-		if n < 0 {
-			n = 0
+			offset = &n
 		}
-
-		offset = &n
 	}
 
 	return
 }
 
 func (d *Decoder[T]) reservedKeys() []string {
-	return []string{QueryAnd, QueryOr, d.querySort, d.queryLimit, d.queryOffset}
+	keys := []string{QueryAnd, QueryOr, d.querySort, d.queryLimit, d.queryOffset, d.queryAfter, d.queryBefore}
+
+	return append(keys, d.reserved...)
+}
+
+// parseIgnored removes the ignored keys, and the unknown fields if
+// ignoreUnknown is set, returning them as warnings.
+func (d *Decoder[T]) parseIgnored(values url.Values) (url.Values, DecodeErrors) {
+	if len(d.ignoreKeys) == 0 && !d.ignoreUnknown {
+		return values, nil
+	}
+
+	reserved := make(map[string]bool)
+	for _, key := range d.reservedKeys() {
+		reserved[key] = true
+	}
+
+	var warns DecodeErrors
+
+	res := make(url.Values)
+	for key, vals := range values {
+		if reserved[key] {
+			res[key] = vals
+
+			continue
+		}
+
+		err := d.ignored(key, vals)
+		if err == nil {
+			res[key] = vals
+
+			continue
+		}
+
+		for _, val := range vals {
+			warns = append(warns, &DecodeError{
+				Key:   key,
+				Value: val,
+				Err:   err,
+			})
+		}
+	}
+
+	return res, warns
 }
 
-func (d *Decoder[T]) parseFilter(values url.Values) (ands, ors []FieldSet, err error) {
+// ignored returns the warning if the key is ignored, or it is an unknown field
+// and ignoreUnknown is set. The same rule applies to the keys in the `and` and
+// `or` groups.
+func (d *Decoder[T]) ignored(key string, vals []string) error {
+	if matchKeys(d.ignoreKeys, key) {
+		return fmt.Errorf("%w: %s", ErrIgnoredKey, key)
+	}
+
+	if !d.ignoreUnknown {
+		return nil
+	}
+
+	// Only the unknown fields are ignored. Invalid ops for known fields are
+	// still errors.
+	field := NewQuery(key, vals).Field
+	if _, _, ok := d.lookup(field); !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownField, field)
+	}
+
+	return nil
+}
+
+// matchKeys returns true if the key matches any of the patterns. A pattern
+// ending with `*` matches the prefix, e.g. `utm_*`.
+func matchKeys(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "*") && strings.HasPrefix(key, strings.TrimSuffix(p, "*")) {
+			return true
+		}
+
+		if p == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Decoder[T]) parseFilter(values url.Values) (ands, ors []FieldSet, errs, warns DecodeErrors) {
 	baseValues := FilterValues(values, d.reservedKeys()...)
 
 	// Base values are the same as AND values.
@@ -298,113 +614,546 @@ func (d *Decoder[T]) parseFilter(values url.Values) (ands, ors []FieldSet, err e
 		}
 	}
 
+	// The groups are checked before they are split.
+	if max := d.limits.ValueLength; max > 0 {
+		for _, key := range []string{QueryAnd, QueryOr} {
+			for _, v := range values[key] {
+				if len(v) > max {
+					return nil, nil, DecodeErrors{&DecodeError{
+						Key:   key,
+						Value: v,
+						Err:   fmt.Errorf("%w: max length %d", ErrValueTooLong, max),
+					}}, nil
+				}
+			}
+		}
+	}
+
 	andValues = append(andValues, values[QueryAnd]...)
 
-	ands, err = d.decodeConjunction(OpAnd, andValues)
-	if err != nil {
-		return
+	c := newCounter(d.limits)
+
+	ands, aerrs, awarns := d.decodeConjunction(OpAnd, andValues, 0, c)
+	for _, de := range awarns {
+		de.Key = QueryAnd
 	}
 
-	ors, err = d.decodeConjunction(OpOr, values[QueryOr])
-	if err != nil {
-		return
+	for _, de := range aerrs {
+		de.Key = QueryAnd
+
+		// The error is from the base values, e.g. `name.eq=john`.
+		if len(de.Path) == 0 {
+			for key, vals := range baseValues {
+				q := NewQuery(key, vals)
+				if q.Field == de.Field && q.Op == de.Op && includes(vals, de.Value) {
+					de.Key = key
+				}
+			}
+		}
 	}
 
+	if len(aerrs) > 0 && (!d.collectErrors || c.err != nil) {
+		return nil, nil, aerrs, nil
+	}
+
+	ors, oerrs, owarns := d.decodeConjunction(OpOr, values[QueryOr], 0, c)
+	for _, de := range oerrs {
+		de.Key = QueryOr
+	}
+
+	for _, de := range owarns {
+		de.Key = QueryOr
+	}
+
+	errs = append(aerrs, oerrs...)
+	warns = append(awarns, owarns...)
+
 	return
 }
 
-func (d *Decoder[T]) parseSort(values url.Values) ([]Order, error) {
-	sort, err := ParseOrder(values[d.querySort])
-	if err != nil {
-		return nil, err
+func (d *Decoder[T]) parseSort(values url.Values) ([]Order, DecodeErrors) {
+	var errs DecodeErrors
+
+	decodeErr := func(field, value string, err error) {
+		errs = append(errs, &DecodeError{
+			Key:   d.querySort,
+			Field: field,
+			Value: value,
+			Err:   err,
+		})
+	}
+
+	seen := make(map[string]bool)
+
+	sorts := make([]Order, 0, len(values[d.querySort]))
+	for _, v := range values[d.querySort] {
+		s, err := d.parseOrder(v)
+		if err != nil {
+			decodeErr("", v, err)
+
+			continue
+		}
+
+		if s == nil {
+			continue
+		}
+
+		// Unknown or unsortable fields are ignored, unless strict.
+		tag, ok := d.tags[s.Field]
+		if !ok || !tag.Sort {
+			if d.strictSort {
+				decodeErr(s.Field, v, fmt.Errorf("%w: %s", ErrUnsortableField, s.Field))
+			}
+
+			continue
+		}
+
+		// Only the first of the duplicate fields is kept, unless strict.
+		if seen[s.Field] {
+			if d.strictSort {
+				decodeErr(s.Field, v, fmt.Errorf("%w: %s", ErrDuplicateSortField, s.Field))
+			}
+
+			continue
+		}
+
+		seen[s.Field] = true
+
+		if d.sortMax > 0 && len(sorts) == d.sortMax {
+			decodeErr(s.Field, v, fmt.Errorf("%w: max %d", ErrTooManySortFields, d.sortMax))
+
+			continue
+		}
+
+		s.Column = tag.Column
+		sorts = append(sorts, *s)
+	}
+
+	// The default sort is resolved to the columns of the current tags.
+	if len(sorts) == 0 {
+		for _, o := range d.defaultSort {
+			if tag, ok := d.tags[o.Field]; ok && tag.Sort {
+				o.Column = tag.Column
+				sorts = append(sorts, o)
+			}
+		}
+	}
+
+	return d.appendPK(sorts), errs
+}
+
+// appendPK appends the pk as the tie-breaker of the sort, if it is not
+// already sorted. The pk has the same direction as the last key.
+func (d *Decoder[T]) appendPK(sorts []Order) []Order {
+	var pk *Tag
+	for _, tag := range d.tags {
+		if tag.PK {
+			pk = tag
+		}
+	}
+
+	if pk == nil {
+		return sorts
+	}
+
+	dir := SortDirectionAscending
+	for _, o := range sorts {
+		if o.Field == pk.Name {
+			return sorts
+		}
+
+		dir = o.Direction
+	}
+
+	return append(sorts, Order{
+		Field:     pk.Name,
+		Direction: dir,
+		Option:    dir.DefaultOption(),
+		Column:    pk.Column,
+	})
+}
+
+// SetStrictSort returns ErrUnsortableField for the sort fields that are
+// unknown or not sortable, and ErrDuplicateSortField for the fields that are
+// sorted more than once. Otherwise, they are ignored.
+func (d *Decoder[T]) SetStrictSort(strict bool) *Decoder[T] {
+	d.strictSort = strict
+
+	return d
+}
+
+// SetSortMax sets the maximum number of sort fields, excluding the pk. Zero
+// means no limit.
+func (d *Decoder[T]) SetSortMax(n int) *Decoder[T] {
+	if err := d.setSortMax(n); err != nil {
+		panic(err)
 	}
 
-	validSortByField := make(map[string]bool)
-	for field, tag := range d.tags {
-		validSortByField[field] = tag.Sort
+	return d
+}
+
+// SetDefaultSort sets the sort when the query string has none. The direction
+// and option defaults to ascending with nulls last. The fields must be
+// sortable, which is checked by Validate.
+func (d *Decoder[T]) SetDefaultSort(orders ...Order) *Decoder[T] {
+	if err := d.setDefaultSort(orders...); err != nil {
+		panic(err)
 	}
 
-	sorts := make([]Order, 0, len(sort))
-	for _, s := range sort {
-		if validSortByField[s.Field] {
-			sorts = append(sorts, s)
+	return d
+}
+
+// parseOrder parses the order, matching the longest field name so that nested
+// fields, e.g. `address.city.desc`, are not mistaken for the direction.
+func (d *Decoder[T]) parseOrder(s string) (*Order, error) {
+	var field string
+	for name := range d.tags {
+		if len(name) <= len(field) || !strings.HasPrefix(s, name) {
+			continue
+		}
+
+		if len(s) == len(name) || s[len(name)] == '.' {
+			field = name
 		}
 	}
 
-	return sorts, nil
+	if field == "" {
+		return NewOrder(s)
+	}
+
+	return newOrder(field, strings.TrimPrefix(s[len(field):], "."))
 }
 
-func (d *Decoder[T]) decodeField(query Query) (*FieldSet, error) {
+func (d *Decoder[T]) decodeField(query Query) (*FieldSet, DecodeErrors) {
 	field, op, values := query.Field, query.Op, query.Values
 
-	tag, ok := d.tags[field]
+	decodeErr := func(value string, err error) DecodeErrors {
+		return DecodeErrors{&DecodeError{
+			Field: field,
+			Op:    op,
+			Value: value,
+			Err:   err,
+		}}
+	}
+
+	if max := d.limits.ValueLength; max > 0 {
+		for _, value := range values {
+			if len(value) > max {
+				return nil, decodeErr(value, fmt.Errorf("%w: max length %d", ErrValueTooLong, max))
+			}
+		}
+	}
+
+	tag, path, ok := d.lookup(field)
 	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrUnknownField, field)
+		return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownField, field))
 	}
 
-	if ok := tag.Ops.Has(op); !ok {
-		return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, query)
+	// The values of the JSON paths are compared with the ops of the path's
+	// type instead.
+	typ, ops, rt := tag.Type, tag.Ops, d.goType(tag, path)
+	if len(path) > 0 {
+		if _, ok := d.dialect.(PathDialect); !ok {
+			return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnsupportedOp, query))
+		}
+
+		typ = tag.PathType(path)
+		ops = NewOps(typ)
+
+		// The ops of the field, e.g. `attrs,ops:haskey`, also restricts the
+		// ops of the paths.
+		if tag.Ops != NewOps(tag.Type) {
+			ops &= tag.Ops
+		}
+	} else if typ.Inet() && !typ.Array && tag.Ops == NewOps(typ) {
+		// IP addresses and networks can be contained by the networks, when
+		// the dialect supports it, e.g. Postgres.
+		ops |= d.dialect.Ops(typ) & OpsInet
 	}
 
-	parser, ok := d.parsers[tag.Type.Name]
+	// The cardinality of the array is compared as an int. Fields must opt in
+	// with the `len` op.
+	if query.Len {
+		if ok := typ.Array && !typ.JSON() && ops.Has(OpLen); !ok {
+			return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownOperator, query))
+		}
+
+		if ok := d.dialect.Ops(typ).Has(OpLen); !ok {
+			return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnsupportedOp, query))
+		}
+
+		typ = Type{Name: "int"}
+		ops = NewOps(typ)
+		rt = nil
+	}
+
+	// The quantified ops compares a single element of the array, which is
+	// parsed with the element type's parser.
+	quantifier := query.Quantifier
+	if quantifier.Valid() {
+		if ok := typ.Array && !typ.JSON() && ops.Has(quantifier); !ok {
+			return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownOperator, query))
+		}
+
+		if ok := d.dialect.Ops(typ).Has(quantifier); !ok {
+			return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnsupportedOp, query))
+		}
+
+		typ = elemType(typ)
+
+		// Only strings can be matched by patterns.
+		if ok := strings.TrimPrefix(typ.Name, "*") == "string"; OpsLike.Has(op) && !ok {
+			return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownOperator, query))
+		}
+	} else if ok := ops.Has(op); !ok {
+		return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownOperator, query))
+	}
+
+	if ok := ops.Has(OpNot); query.Negated && !ok {
+		return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownOperator, query))
+	}
+
+	if ok := d.dialect.Ops(typ).Has(op); !ok && !quantifier.Valid() {
+		return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnsupportedOp, query))
+	}
+
+	parser, ok := d.parser(typ, rt)
 	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrUnknownParser, tag.Type.Name)
+		return nil, decodeErr(values[0], fmt.Errorf("%w: %s", ErrUnknownParser, typ.Name))
+	}
+
+	// The JSON keys are strings.
+	if op.Is(OpHasKey) || op.Is(OpHasAnyKeys) || op.Is(OpHasAllKeys) {
+		parser = ParseString
+	}
+
+	// The IP addresses are contained by the networks, and vice versa.
+	if typ.Inet() && OpsInet.Has(op) {
+		parser = ParseInet
+	}
+
+	// The layouts of the field overrides the TimeFormat of the decoder.
+	format := d.timeFormat
+	if len(tag.Layouts) > 0 {
+		format.Layouts = tag.Layouts
+	}
+
+	// The time.Time values are parsed with the TimeFormat, unless a parser
+	// is registered for time.Time.
+	elem := strings.TrimPrefix(typ.Name, "[]")
+	isPtr := strings.HasPrefix(elem, "*")
+	isTime := strings.TrimPrefix(elem, "*") == "time.Time" && len(path) == 0 && (len(tag.Layouts) > 0 || d.formatsTime())
+	if isTime {
+		parser = format.Parse
+		if isPtr {
+			parser = ParsePointer(parser, timeType)
+		}
 	}
 
 	fs := FieldSet{
-		Tag:    tag,
-		Name:   field,
-		Op:     op,
-		Values: values,
+		Tag:        tag,
+		Name:       tag.Name,
+		Column:     tag.Column,
+		Op:         op,
+		Values:     values,
+		Negated:    query.Negated,
+		Path:       path,
+		Quantifier: quantifier,
+		Len:        query.Len,
 	}
 
 	switch {
-	case OpsMany.Has(op), tag.Type.Array:
-		res, err := Map(values, parser)
+	case OpsBetween.Has(op):
+		r, err := ParseRange(values, parser)
 		if err != nil {
-			return nil, err
+			return nil, decodeErr(values[0], err)
+		}
+
+		fs.Value = *r
+	case OpsMany.Has(op) && !quantifier.Valid(), typ.Array && !typ.JSON():
+		if max := d.limits.Values; max > 0 && len(values) > max {
+			return nil, decodeErr(values[max], fmt.Errorf("%w: max %d", ErrTooManyValues, max))
+		}
+
+		var errs DecodeErrors
+
+		res := make([]any, len(values))
+		for i, value := range values {
+			v, err := parser(value)
+			if err != nil {
+				errs = append(errs, decodeErr(value, err)...)
+
+				continue
+			}
+
+			res[i] = v
+		}
+
+		if len(errs) > 0 {
+			return nil, errs
 		}
 
 		fs.Value = res
 	default:
 		if len(values) > 1 {
-			return nil, fmt.Errorf("%w: %s", ErrTooManyValues, query)
+			return nil, decodeErr(values[1], fmt.Errorf("%w: %s", ErrTooManyValues, query))
 		}
 
 		value, _ := Unquote(values[0], '"', '"')
-		res, err := parser(value)
+		if !isTime || value == "null" {
+			res, err := parser(value)
+			if err != nil {
+				return nil, decodeErr(values[0], err)
+			}
+
+			fs.Value = res
+
+			break
+		}
+
+		t, date, err := format.parse(value)
 		if err != nil {
-			return nil, err
+			return nil, decodeErr(values[0], err)
 		}
 
-		fs.Value = res
+		// The date is compared with the whole day of the timestamps, e.g.
+		// `created_at.eq=2006-01-02` is `created_at.between=[2006-01-02,2006-01-03)`.
+		switch {
+		case date && !quantifier.Valid() && (op.Is(OpEq) || op.Is(OpNeq)):
+			fs.Op = OpBetween
+			if op.Is(OpNeq) {
+				fs.Op = OpNotBetween
+			}
+
+			fs.Value = dateRange(t)
+		case isPtr:
+			fs.Value = &t
+		default:
+			fs.Value = t
+		}
 	}
 
 	return &fs, nil
 }
 
-func (d *Decoder[T]) decodeFields(values url.Values) ([]FieldSet, error) {
+// fieldName returns the name of the field, without the JSON path, e.g.
+// `attrs` for `attrs.color`.
+func (d *Decoder[T]) fieldName(field string) string {
+	if tag, _, ok := d.lookup(field); ok {
+		return tag.Name
+	}
+
+	return field
+}
+
+// lookup returns the tag of the field. Fields that are not found may be the
+// path of a JSON field, e.g. `attrs.color` or `attrs->color`.
+func (d *Decoder[T]) lookup(field string) (*Tag, []string, bool) {
+	if tag, ok := d.tags[field]; ok {
+		return tag, nil, true
+	}
+
+	field = strings.ReplaceAll(field, "->", ".")
+
+	var res *Tag
+	for name, tag := range d.tags {
+		if !tag.Type.JSON() || !strings.HasPrefix(field, name+".") {
+			continue
+		}
+
+		if res == nil || len(name) > len(res.Name) {
+			res = tag
+		}
+	}
+
+	if res == nil {
+		return nil, nil, false
+	}
+
+	path := strings.Split(strings.TrimPrefix(field, res.Name+"."), ".")
+	for _, p := range path {
+		if !wordRe.MatchString(p) {
+			return nil, nil, false
+		}
+	}
+
+	return res, path, true
+}
+
+func (d *Decoder[T]) decodeFields(values url.Values, c *counter) ([]FieldSet, DecodeErrors) {
+	var errs DecodeErrors
+
 	res := make([]FieldSet, 0, len(values))
 
 	values = FilterValues(values, d.reservedKeys()...)
-	queries, err := ParseQuery(values)
-	if err != nil {
-		return nil, err
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	queries := make([]Query, 0, len(keys))
+	for _, key := range keys {
+		if len(errs) > 0 && !d.collectErrors {
+			return nil, errs
+		}
+
+		vals := values[key]
+		if len(vals) == 0 {
+			continue
+		}
+
+		query := NewQuery(key, vals)
+		if err := query.Validate(); err != nil {
+			errs = append(errs, &DecodeError{
+				Field: query.Field,
+				Op:    query.Op,
+				Value: vals[0],
+				Err:   err,
+			})
+
+			continue
+		}
+
+		queries = append(queries, *query)
 	}
 
+	SortQuery(queries)
+
 	for _, query := range queries {
-		fs, err := d.decodeField(query)
-		if err != nil {
-			return nil, err
+		if len(errs) > 0 && !d.collectErrors {
+			return nil, errs
+		}
+
+		// The limits are checked before decoding, and stops the decoding
+		// even if the errors are collected.
+		if err := c.add(d.fieldName(query.Field)); err != nil {
+			return nil, append(errs, &DecodeError{
+				Field: query.Field,
+				Op:    query.Op,
+				Value: query.Values[0],
+				Err:   err,
+			})
+		}
+
+		fs, ferrs := d.decodeField(query)
+		if len(ferrs) > 0 {
+			errs = append(errs, ferrs...)
+
+			continue
 		}
 
 		res = append(res, *fs)
 	}
 
-	return res, nil
+	return res, errs
 }
 
-func (d *Decoder[T]) decodeConjunction(conj Op, values []string) ([]FieldSet, error) {
+// decodeConjunction decodes the field sets of the conjunction. The ignored
+// keys are returned as the warnings, see Decoder.SetIgnoreUnknown.
+func (d *Decoder[T]) decodeConjunction(conj Op, values []string, depth int, c *counter) ([]FieldSet, DecodeErrors, DecodeErrors) {
 	switch conj {
 	case OpAnd, OpOr:
 	default:
@@ -414,73 +1163,150 @@ func (d *Decoder[T]) decodeConjunction(conj Op, values []string) ([]FieldSet, er
 	values = Unique(values)
 	sort.Strings(values)
 
+	var errs, warns DecodeErrors
+
 	conjs := make([]FieldSet, 0, len(values))
 
 	uvals := make(url.Values)
 
 	for _, value := range values {
+		// The decoding stops at the first error, unless the errors are
+		// collected.
+		if len(errs) > 0 && (!d.collectErrors || c.err != nil) {
+			return nil, errs, nil
+		}
+
 		field, opv := Split2(value, ".")
 
+		// The conjunction may be negated, e.g. `not.or.(age.gt:10,age.lt:20)`.
+		negated := field == OpNot.String()
+		if negated {
+			field, opv = Split2(opv, ".")
+		}
+
 		switch field {
-		case OpOr.String():
+		case OpOr.String(), OpAnd.String():
+			op := OpOr
+			if field == OpAnd.String() {
+				op = OpAnd
+			}
+
 			vl, ok := Unquote(opv, '(', ')')
 			if !ok {
-				return nil, fmt.Errorf("%w: %s", ErrInvalidConjunction, opv)
+				errs = append(errs, &DecodeError{
+					Path:  []string{field},
+					Op:    op,
+					Value: value,
+					Err:   fmt.Errorf("%w: %s", ErrInvalidConjunction, opv),
+				})
+
+				continue
 			}
 
-			vals := SplitOutsideBrackets(vl)
-			ors, err := d.decodeConjunction(OpOr, vals)
-			if err != nil {
-				return nil, err
+			// The depth is checked before recursing, so that deeply nested
+			// conjunctions cannot blow the stack.
+			if max := d.limits.Depth; max > 0 && depth+1 > max {
+				errs = append(errs, &DecodeError{
+					Path:  []string{field},
+					Op:    op,
+					Value: value,
+					Err:   fmt.Errorf("%w: max depth %d", ErrTooDeep, max),
+				})
+
+				continue
 			}
 
-			fs := FieldSet{
-				Name:   conj.String(),
-				Op:     OpOr,
-				Values: vals,
-				Or:     ors,
+			vals := SplitOutsideBrackets(vl)
+			sets, cerrs, cwarns := d.decodeConjunction(op, vals, depth+1, c)
+			for _, de := range cwarns {
+				de.Path = append([]string{field}, de.Path...)
 			}
 
-			conjs = append(conjs, fs)
+			warns = append(warns, cwarns...)
 
-		case OpAnd.String():
-			vl, ok := Unquote(opv, '(', ')')
-			if !ok {
-				return nil, fmt.Errorf("%w: %s", ErrInvalidConjunction, opv)
-			}
+			if len(cerrs) > 0 {
+				for _, de := range cerrs {
+					de.Path = append([]string{field}, de.Path...)
+				}
 
-			vals := SplitOutsideBrackets(vl)
+				errs = append(errs, cerrs...)
 
-			ands, err := d.decodeConjunction(OpAnd, vals)
-			if err != nil {
-				return nil, err
+				continue
+			}
+
+			// The group is dropped when all of its keys are ignored.
+			if len(sets) == 0 && len(cwarns) > 0 {
+				continue
 			}
 
 			fs := FieldSet{
-				Name:   conj.String(),
-				Op:     OpAnd,
-				Values: vals,
-				And:    ands,
+				Name:    conj.String(),
+				Op:      op,
+				Values:  vals,
+				Negated: negated,
+			}
+
+			if op == OpAnd {
+				fs.And = sets
+			} else {
+				fs.Or = sets
 			}
 
 			conjs = append(conjs, fs)
 
 		default:
+			// Only conjunctions can be negated with the `not` prefix. Fields
+			// are negated with the `not` before the operator, e.g.
+			// `age.not.gt:10`.
+			if negated {
+				errs = append(errs, &DecodeError{
+					Op:    OpNot,
+					Value: value,
+					Err:   fmt.Errorf("%w: %s", ErrInvalidConjunction, value),
+				})
+
+				continue
+			}
+
 			// The `AND` may contain `in` operators, e.g.
 			// and=name.in:alice&and=name.in:bob
 			// We need to combine them to `name.in=[]string{alice, bob}` before
 			// parsing.
 			k, v := Split2(value, ":")
+			if err := d.ignored(k, []string{v}); err != nil {
+				warns = append(warns, &DecodeError{
+					Value: value,
+					Err:   err,
+				})
+
+				continue
+			}
+
 			uvals.Add(k, v)
 		}
 	}
 
-	innerConjs, err := d.decodeFields(uvals)
-	if err != nil {
-		return nil, err
+	if len(errs) > 0 && (!d.collectErrors || c.err != nil) {
+		return nil, errs, nil
+	}
+
+	innerConjs, ferrs := d.decodeFields(uvals, c)
+	errs = append(errs, ferrs...)
+	if len(errs) > 0 {
+		return nil, errs, nil
 	}
 
 	conjs = append(innerConjs, conjs...)
 
-	return conjs, nil
+	return conjs, nil, warns
+}
+
+func includes(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
@@ -2,15 +2,19 @@ package goql_test
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/netip"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 )
 
@@ -39,7 +43,7 @@ func TestDecoderCustomStructTag(t *testing.T) {
 
 func TestDecoderCustomParser(t *testing.T) {
 	type Book struct {
-		ID uuid.UUID `q:"id,type:uuid"`
+		ID uuid.UUID `q:"id,type:guid"`
 	}
 
 	id := uuid.New()
@@ -59,7 +63,7 @@ func TestDecoderCustomParser(t *testing.T) {
 
 	t.Logf("validateError: %s", err)
 
-	dec.SetParser("uuid", parseUUID)
+	dec.SetParser("guid", parseUUID)
 	err = dec.Validate()
 	if err != nil {
 		t.Fatal(err)
@@ -323,11 +327,13 @@ func TestSort(t *testing.T) {
 			Field:     "id",
 			Direction: "desc",
 			Option:    "nullslast",
+			Column:    "id",
 		}
 		byName := goql.Order{
 			Field:     "name",
 			Direction: "asc",
 			Option:    "nullsfirst",
+			Column:    "name",
 		}
 
 		if exp, got := byID, f.Sort[0]; exp != got {
@@ -607,3 +613,810 @@ func init() {
 	}
 
 }
+
+func TestDecoderColumn(t *testing.T) {
+	type Book struct {
+		Title       string `q:"title,col:b.title"`
+		PublishYear int    `q:"publish_year" sort:"true"`
+	}
+
+	dec := goql.NewDecoder[Book]()
+	dec.SetColumn("publish_year", `"b"."published_year"`)
+
+	t.Run("resolved column", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("title.eq", "Mastery")
+		v.Set("publish_year.gt", "2010")
+		v.Set("sort_by", "publish_year.desc")
+
+		f, err := dec.Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := `"b"."published_year"`, f.And[0].Column; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := "b.title", f.And[1].Column; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := `"b"."published_year"`, f.Sort[0].Column; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("column is not a public name", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("published_year.gt", "2010")
+
+		_, err := dec.Decode(v)
+		if !errors.Is(err, goql.ErrUnknownField) {
+			t.Fatalf("expected %v, got %v", goql.ErrUnknownField, err)
+		}
+	})
+}
+
+func TestDecoderNestedStruct(t *testing.T) {
+	type Timestamps struct {
+		CreatedAt time.Time `sort:"true"`
+		UpdatedAt *time.Time
+	}
+
+	type Address struct {
+		City     string `sort:"true"`
+		PostCode int    `q:"post_code"`
+	}
+
+	type User struct {
+		ID int `sort:"true"`
+		*Timestamps
+		Address Address  `q:"addr"`
+		Billing *Address `q:"billing,col:b"`
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	t.Run("embedded", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("createdAt.gt", "2022-01-01T00:00:00Z")
+		v.Set("updatedAt.isnot", "null")
+		v.Set("sort_by", "createdAt.desc")
+
+		f, err := dec.Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names := []string{f.And[0].Name, f.And[1].Name, f.Sort[0].Field}
+		if diff := cmp.Diff([]string{"createdAt", "updatedAt", "createdAt"}, names); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("addr.city.eq", "KL")
+		v.Add("billing.post_code.in", "1")
+		v.Add("billing.post_code.in", "2")
+		v.Set("or", "addr.city.like:K%")
+		v.Set("sort_by", "addr.city.desc")
+
+		f, err := dec.Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		where, args, err := goql.BuildSQL(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := `WHERE addr.city = $1 AND b.post_code in ($2, $3) OR addr.city like $4 ORDER BY addr.city DESC NULLS FIRST`, where; exp != got {
+			t.Fatalf("expected %q, got %q", exp, got)
+		}
+
+		if diff := cmp.Diff([]any{"KL", 1, 2, "K%"}, args); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+	})
+
+	t.Run("unknown nested field", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("address.city.eq", "KL")
+
+		_, err := dec.Decode(v)
+		if !errors.Is(err, goql.ErrUnknownField) {
+			t.Fatalf("expected %v, got %v", goql.ErrUnknownField, err)
+		}
+	})
+}
+
+func TestDecoderNegation(t *testing.T) {
+	type User struct {
+		Name string `q:"name,ops:eq"`
+		Age  int    `q:"age,ops:eq,gt,not"`
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	t.Run("negated field", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("age.not.gt", "10")
+
+		f, err := dec.Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := true, f.And[0].Negated; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := goql.OpGt, f.And[0].Op; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("negated group", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("and", "not.or.(age.eq:1,name.eq:john)")
+
+		f, err := dec.Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := true, f.And[0].Negated; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := 2, len(f.And[0].Or); exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{"negation not allowed", "name.not.eq=john", goql.ErrUnknownOperator},
+		{"not without op", "age.not=10", goql.ErrUnknownOperator},
+		{"negated field in conjunction", "and=not.age.eq:10", goql.ErrInvalidConjunction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestDecoderQuantifier(t *testing.T) {
+	type Post struct {
+		Title  string
+		Scores []int    `q:"scores,type:[]int"`
+		Tags   []string `q:"tags,type:[]string,ops:eq,any"`
+	}
+
+	dec := goql.NewDecoder[Post]()
+
+	t.Run("quantified", func(t *testing.T) {
+		f, err := dec.DecodeString("scores.anygt=50")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fs := f.And[0]
+		if exp, got := goql.OpAny, fs.Quantifier; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := goql.OpGt, fs.Op; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := any(50), fs.Value; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{"not an array", "title.anyeq=go", goql.ErrUnknownOperator},
+		{"pattern on int", "scores.anylike=1", goql.ErrUnknownOperator},
+		{"quantifier not allowed", "tags.alleq=go", goql.ErrUnknownOperator},
+		{"quantifier only", "tags.any=go", goql.ErrUnknownOperator},
+		{"not quantifiable", "scores.anyin=1", goql.ErrUnknownOperator},
+		{"too many values", "scores.anyeq=1&scores.anyeq=2", goql.ErrTooManyValues},
+		{"bad value", "scores.alllt=one", goql.ErrBadValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestDecoderLen(t *testing.T) {
+	type User struct {
+		Name    string   `q:"name,ops:eq,len"`
+		Tags    []string `q:"tags,type:[]string"`
+		Hobbies []string `q:"hobbies,type:[]string,ops:eq,len"`
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	t.Run("len", func(t *testing.T) {
+		f, err := dec.DecodeString("hobbies.len.eq=0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fs := f.And[0]
+		if exp, got := true, fs.Len; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := any(0), fs.Value; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if exp, got := "0", dec.Encoder().Encode(f).Get("hobbies.len.eq"); exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{"not opt in", "tags.len.gt=3", goql.ErrUnknownOperator},
+		{"not an array", "name.len.eq=3", goql.ErrUnknownOperator},
+		{"len only", "hobbies.len=3", goql.ErrUnknownOperator},
+		{"bad value", "hobbies.len.gt=many", goql.ErrBadValue},
+		{"not comparable", "hobbies.len.like=1", goql.ErrUnknownOperator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestDefaultSort(t *testing.T) {
+	type User struct {
+		ID   int    `sort:"true,pk"`
+		Name string `sort:"true"`
+		Age  int    `sort:"true"`
+	}
+
+	dec := goql.NewDecoder[User]().SetDefaultSort(goql.Order{Field: "name", Direction: goql.SortDirectionDescending})
+
+	tests := []struct {
+		name  string
+		query string
+		exp   []string
+	}{
+		{"default", "", []string{"name.desc.nullsfirst", "id.desc.nullsfirst"}},
+		{"sorted", "sort_by=age", []string{"age.asc.nullslast", "id.asc.nullslast"}},
+		{"last direction", "sort_by=age&sort_by=name.desc.nullslast", []string{"age.asc.nullslast", "name.desc.nullslast", "id.desc.nullsfirst"}},
+		{"pk sorted", "sort_by=id.desc&sort_by=age", []string{"id.desc.nullsfirst", "age.asc.nullslast"}},
+		{"unknown fields", "sort_by=email", []string{"name.desc.nullsfirst", "id.desc.nullsfirst"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, len(f.Sort))
+			for i, o := range f.Sort {
+				got[i] = o.Query()
+			}
+
+			if diff := cmp.Diff(tt.exp, got); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+
+	t.Run("column after default", func(t *testing.T) {
+		dec := goql.NewDecoder[User]().SetDefaultSort(goql.Order{Field: "age"}).SetColumn("age", "u.age")
+
+		f, err := dec.DecodeString("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := "u.age", f.Sort[0].Column; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("unsortable after sort tag", func(t *testing.T) {
+		dec := goql.NewDecoder[User]().SetDefaultSort(goql.Order{Field: "age"}).SetSortTag("order")
+
+		_, err := dec.DecodeString("")
+		if !errors.Is(err, goql.ErrUnsortableField) {
+			t.Fatalf("expected %v, got %v", goql.ErrUnsortableField, err)
+		}
+	})
+
+	t.Run("pk without default", func(t *testing.T) {
+		f, err := goql.NewDecoder[User]().DecodeString("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := []goql.Order{{Field: "id", Direction: "asc", Option: "nullslast", Column: "id"}}, f.Sort; !reflect.DeepEqual(exp, got) {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("multiple pk", func(t *testing.T) {
+		type Account struct {
+			ID   int    `sort:"true,pk"`
+			Code string `sort:"true,pk"`
+		}
+
+		_, err := goql.ParseStruct(Account{}, goql.TagFilter, goql.TagSort)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
+
+func TestStrictSort(t *testing.T) {
+	type User struct {
+		ID    int    `sort:"true,pk"`
+		Name  string `sort:"true"`
+		Age   int    `sort:"true"`
+		Email string
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		max   int
+		err   error
+	}{
+		{"valid", "sort_by=age&sort_by=name.desc", 0, nil},
+		{"unknown field", "sort_by=created_at", 0, goql.ErrUnsortableField},
+		{"unsortable field", "sort_by=email", 0, goql.ErrUnsortableField},
+		{"duplicate field", "sort_by=age.asc&sort_by=age.desc", 0, goql.ErrDuplicateSortField},
+		{"max excludes pk", "sort_by=age&sort_by=name", 2, nil},
+		{"too many fields", "sort_by=age&sort_by=name&sort_by=id", 2, goql.ErrTooManySortFields},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := goql.NewDecoder[User]().SetStrictSort(true).SetSortMax(tt.max)
+
+			_, err := dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("duplicate field not strict", func(t *testing.T) {
+		f, err := goql.NewDecoder[User]().DecodeString("sort_by=age.asc&sort_by=age.desc&sort_by=name")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := make([]string, len(f.Sort))
+		for i, o := range f.Sort {
+			fields[i] = o.Query()
+		}
+
+		if diff := cmp.Diff([]string{"age.asc.nullslast", "name.asc.nullslast", "id.asc.nullslast"}, fields); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+	})
+
+	t.Run("error field", func(t *testing.T) {
+		_, err := goql.NewDecoder[User]().SetStrictSort(true).DecodeString("sort_by=email.desc")
+
+		var de *goql.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected DecodeError, got %v", err)
+		}
+
+		if exp, got := "email", de.Field; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+}
+
+func TestDecoderIgnore(t *testing.T) {
+	type User struct {
+		Name string
+	}
+
+	tests := []struct {
+		name     string
+		dec      *goql.Decoder[User]
+		query    string
+		warnings []string
+		err      error
+	}{
+		{"strict", goql.NewDecoder[User](), "name.eq=john&utm_source=x", nil, goql.ErrUnknownOperator},
+		{"ignore keys", goql.NewDecoder[User]().SetIgnoreKeys("utm_*", "_t"), "name.eq=john&utm_source=x&_t=1", []string{"utm_source", "_t"}, nil},
+		{"ignore keys unmatched", goql.NewDecoder[User]().SetIgnoreKeys("utm_*"), "name.eq=john&_t.eq=1", nil, goql.ErrUnknownField},
+		{"ignore unknown", goql.NewDecoder[User]().SetIgnoreUnknown(true), "flag.beta=1&name.eq=john&_t=1", []string{"flag.beta", "_t"}, nil},
+		{"ignore unknown invalid op", goql.NewDecoder[User]().SetIgnoreUnknown(true), "name.foo=john", nil, goql.ErrUnknownOperator},
+		{"ignore unknown in groups", goql.NewDecoder[User]().SetIgnoreUnknown(true), "name.eq=john&or=flag.eq:1&and=or.(flag.eq:2)", []string{"or", "and"}, nil},
+		{"ignore keys in groups", goql.NewDecoder[User]().SetIgnoreKeys("_*"), "name.eq=john&and=_t.eq:1", []string{"and"}, nil},
+		{"unknown in groups", goql.NewDecoder[User]().SetIgnoreKeys("_*"), "name.eq=john&and=flag.eq:1", nil, goql.ErrUnknownField},
+		{"reserved keys", goql.NewDecoder[User]().SetReservedKeys("page"), "name.eq=john&page=2", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			var warnings []string
+			for _, w := range f.Warnings {
+				warnings = append(warnings, w.Key)
+			}
+
+			if diff := cmp.Diff(tt.warnings, warnings); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+
+			if exp, got := 1, len(f.And); exp != got {
+				t.Fatalf("expected %v, got %v", exp, got)
+			}
+		})
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+		Age  int
+	}
+
+	tests := []struct {
+		name   string
+		limits goql.Limits
+		query  string
+		err    error
+	}{
+		{"no limits", goql.Limits{}, "or=and.(or.(and.(age.gt:10)))&id.in=1&id.in=2&id.in=3", nil},
+		{"depth", goql.Limits{Depth: 2}, "or=and.(age.gt:10,or.(age.lt:20))", nil},
+		{"too deep", goql.Limits{Depth: 2}, "or=and.(or.(and.(age.gt:10)))", goql.ErrTooDeep},
+		{"predicates", goql.Limits{Predicates: 2}, "name.eq=john&or=age.gt:10", nil},
+		{"too many predicates", goql.Limits{Predicates: 2}, "name.eq=john&or=and.(age.gt:10,age.lt:20)", goql.ErrTooManyPredicates},
+		{"values", goql.Limits{Values: 2}, "id.in=1&id.in=2", nil},
+		{"too many values", goql.Limits{Values: 2}, "id.in=1&id.in=2&id.in=3", goql.ErrTooManyValues},
+		{"value length", goql.Limits{ValueLength: 4}, "name.eq=john", nil},
+		{"value too long", goql.Limits{ValueLength: 4}, "name.eq=alice", goql.ErrValueTooLong},
+		{"fields", goql.Limits{Fields: 1}, "age.gt=10&age.lt=20", nil},
+		{"too many fields", goql.Limits{Fields: 1}, "age.gt=10&or=name.eq:john", goql.ErrTooManyFields},
+		{"group too long", goql.Limits{ValueLength: 10}, "and=or.(age.gt:1,age.lt:2)", goql.ErrValueTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := goql.NewDecoder[User]().SetLimits(tt.limits).DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("stops decoding", func(t *testing.T) {
+		var n int
+		dec := goql.NewDecoder[User]().
+			SetCollectErrors(true).
+			SetLimits(goql.Limits{Predicates: 2}).
+			SetParser("int", func(in string) (any, error) {
+				n++
+
+				return goql.ParseInt(in)
+			})
+
+		_, err := dec.DecodeString("age.gt=1&age.lt=x&id.eq=y&or=age.eq:4&or=and.(age.neq:5,id.neq:6)")
+		if !errors.Is(err, goql.ErrTooManyPredicates) {
+			t.Fatalf("expected %v, got %v", goql.ErrTooManyPredicates, err)
+		}
+
+		if exp, got := 2, n; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+}
+
+func TestDecoderDerivedParsers(t *testing.T) {
+	type Status string
+
+	type Post struct {
+		Tags     []string
+		Scores   []*int64
+		Status   Status
+		Statuses []Status
+		Rating   *float64
+		Level    *int16
+		Weight   *float32
+		Draft    *Status
+	}
+
+	n := int64(10)
+	rating := 4.5
+	level := int16(3)
+	weight := float32(1.5)
+	draft := Status("draft")
+
+	tests := []struct {
+		name  string
+		dec   *goql.Decoder[Post]
+		query string
+		exp   any
+	}{
+		{"slice", goql.NewDecoder[Post](), "tags.cs=go", []any{"go"}},
+		{"slice of pointers", goql.NewDecoder[Post](), "scores.anyeq=10", &n},
+		{"named type", goql.NewDecoder[Post](), "status.eq=draft", Status("draft")},
+		{"slice of named types", goql.NewDecoder[Post](), "statuses.cs=draft", []any{Status("draft")}},
+		{"pointer", goql.NewDecoder[Post](), "rating.gte=4.5", &rating},
+		{"pointer of int16", goql.NewDecoder[Post](), "level.eq=3", &level},
+		{"pointer of float32", goql.NewDecoder[Post](), "weight.eq=1.5", &weight},
+		{"pointer of named type", goql.NewDecoder[Post](), "draft.eq=draft", &draft},
+		{"null pointer", goql.NewDecoder[Post](), "level.eq=null", (*int16)(nil)},
+		{"registered named type", goql.NewDecoder[Post]().SetParser("goql_test.Status", func(in string) (any, error) {
+			return Status(in), nil
+		}), "status.eq=draft", Status("draft")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.exp, f.And[0].Value); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+type email string
+
+var errInvalidEmail = errors.New("invalid email")
+
+func (e email) Validate() error {
+	if !strings.Contains(string(e), "@") {
+		return errInvalidEmail
+	}
+
+	return nil
+}
+
+type phone string
+
+var errInvalidPhone = errors.New("invalid phone")
+
+func (p *phone) Validate() error {
+	if !strings.HasPrefix(string(*p), "+") {
+		return errInvalidPhone
+	}
+
+	return nil
+}
+
+type cents int64
+
+func (c *cents) UnmarshalJSON(b []byte) error {
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+
+	*c = cents(f * 100)
+
+	return nil
+}
+
+func TestDecoderUnmarshalers(t *testing.T) {
+	type Account struct {
+		ID      uuid.UUID
+		IP      *netip.Addr
+		Name    sql.NullString
+		Balance cents
+		Email   email
+		Emails  []email
+		Phone   phone
+	}
+
+	id := uuid.New()
+	ip := netip.MustParseAddr("10.0.0.1")
+
+	tests := []struct {
+		name  string
+		query string
+		exp   any
+		err   error
+	}{
+		{"text unmarshaler", "id.eq=" + id.String(), id, nil},
+		{"text unmarshaler pointer", "ip.eq=10.0.0.1", &ip, nil},
+		{"text unmarshaler bad value", "id.eq=1", nil, goql.ErrBadValue},
+		{"scanner", "name.eq=john", sql.NullString{String: "john", Valid: true}, nil},
+		{"json unmarshaler", "balance.gt=1.5", cents(150), nil},
+		{"validate", "email.eq=john@mail.com", email("john@mail.com"), nil},
+		{"validate error", "email.eq=john", nil, errInvalidEmail},
+		{"validate array", "emails.cs=john", nil, errInvalidEmail},
+		{"validate bad value", "email.eq=john", nil, goql.ErrBadValue},
+		{"validate pointer receiver", "phone.eq=%2B123", phone("+123"), nil},
+		{"validate pointer receiver error", "phone.eq=123", nil, errInvalidPhone},
+	}
+
+	dec := goql.NewDecoder[Account]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.exp, f.And[0].Value, cmp.Comparer(func(a, b netip.Addr) bool { return a == b })); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoderSetParsersCopy(t *testing.T) {
+	type User struct {
+		Age int
+	}
+
+	parsers := goql.NewParsers()
+	a := goql.NewDecoder[User]().SetParsers(parsers)
+	b := goql.NewDecoder[User]().SetParsers(parsers)
+
+	a.SetParser("int", func(in string) (any, error) {
+		return 0, errors.New("bad int")
+	})
+
+	if _, err := parsers["int"]("1"); err != nil {
+		t.Fatalf("expected the caller's parsers to be unchanged, got %v", err)
+	}
+
+	if _, err := b.DecodeString("age.eq=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.DecodeString("age.eq=1"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestRegisterParser(t *testing.T) {
+	type Money int64
+
+	type Order struct {
+		Total    Money
+		Discount *Money
+		Refunds  []Money
+		Fees     []*Money
+		Tax      Money `q:",type:money"`
+	}
+
+	parseMoney := func(in string) (Money, error) {
+		f, err := strconv.ParseFloat(in, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return Money(f * 100), nil
+	}
+
+	dec := goql.RegisterParser(goql.NewDecoder[Order](), parseMoney)
+	dec.SetParser("money", func(in string) (any, error) {
+		return parseMoney(in)
+	})
+
+	m := Money(150)
+
+	tests := []struct {
+		name  string
+		query string
+		exp   any
+	}{
+		{"value", "total.eq=1.5", m},
+		{"pointer", "discount.eq=1.5", &m},
+		{"slice", "refunds.cs=1.5", []any{m}},
+		{"slice of pointers", "fees.anyeq=1.5", &m},
+		{"alias", "tax.eq=1.5", m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.exp, f.And[0].Value); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoderSetTagKeepsOverrides(t *testing.T) {
+	type User struct {
+		Age int `q:"age" filter:"age"`
+	}
+
+	dec := goql.NewDecoder[User]().
+		SetOps("age", goql.OpEq).
+		SetColumn("age", "u.age").
+		SetFilterTag("filter").
+		SetSortTag("order")
+
+	_, err := dec.DecodeString("age.gt=10")
+	if !errors.Is(err, goql.ErrUnknownOperator) {
+		t.Fatalf("expected %v, got %v", goql.ErrUnknownOperator, err)
+	}
+
+	f, err := dec.DecodeString("age.eq=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, got := "u.age", f.And[0].Column; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		type User struct {
+			Age int `q:"age" filter:"user_age"`
+		}
+
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, goql.ErrUnknownField) {
+				t.Fatalf("expected %v, got %v", goql.ErrUnknownField, err)
+			}
+		}()
+
+		goql.NewDecoder[User]().SetOps("age", goql.OpEq).SetFilterTag("filter")
+	})
+}
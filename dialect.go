@@ -0,0 +1,551 @@
+package goql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnsupportedOp = errors.New("goql: unsupported op")
+)
+
+// Dialect renders the database-specific parts of the SQL.
+type Dialect interface {
+	// Placeholder returns the placeholder for the n-th argument, starting
+	// from 1.
+	Placeholder(n int) string
+
+	// Quote quotes the identifier, if necessary.
+	Quote(ident string) string
+
+	// Ops returns the ops that the dialect can render for the type.
+	Ops(t Type) Op
+
+	// Predicate renders the field set for the column. Values are passed to
+	// bind, which returns the placeholder.
+	Predicate(col string, fs FieldSet, bind func(any) string) (string, error)
+
+	// Order renders a single sort key for the column.
+	Order(col string, o Order) string
+}
+
+// PathDialect is implemented by the dialects that can render the JSON paths,
+// e.g. `attrs.color.eq=red`. The JSON paths fail with ErrUnsupportedOp for
+// the other dialects.
+type PathDialect interface {
+	Dialect
+
+	// Path renders the value of the JSON path in the column, which is
+	// compared as the type.
+	Path(col string, path []string, t Type) string
+}
+
+var (
+	_ PathDialect = Postgres{}
+	_ PathDialect = MySQL{}
+	_ PathDialect = SQLite{}
+)
+
+// Postgres is the default dialect.
+type Postgres struct{}
+
+func (Postgres) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (Postgres) Quote(ident string) string {
+	return quoteIdent(ident, `"`)
+}
+
+func (Postgres) Ops(t Type) Op {
+	return OpsComparable | OpsNull | OpsLike | OpsFullTextSearch | OpsRange | OpsBetween | OpsJSON | OpsQuantifier | OpLen
+}
+
+func (Postgres) Predicate(col string, fs FieldSet, bind func(any) string) (string, error) {
+	if fs.Quantifier.Valid() {
+		return quantified(col, fs, bind)
+	}
+
+	// Null arrays have no elements.
+	if fs.Len {
+		col = fmt.Sprintf("coalesce(cardinality(%s), 0)", col)
+	}
+
+	// The networks are contained with `>>=` and `<<=` instead.
+	if op, ok := inetOps[fs.Op]; ok && fs.Tag != nil && fs.Tag.Type.Inet() && len(fs.Path) == 0 {
+		return fmt.Sprintf("%s %s %s", col, op, bind(fs.Value)), nil
+	}
+
+	switch fs.Op {
+	case OpEq, OpNeq, OpLt, OpLte, OpGt, OpGte, OpCs, OpCd, OpOv, OpSl, OpSr, OpNxr, OpNxl, OpAdj, OpHasKey, OpHasAnyKeys, OpHasAllKeys:
+		return fmt.Sprintf("%s %s %s", col, sqlOps[fs.Op], bindArray(fs.Value, bind)), nil
+
+	case OpLike, OpIlike:
+		return likeQuantified(col, sqlOps[fs.Op], "any", fs.Value, bind), nil
+
+	case OpNotLike, OpNotIlike:
+		return likeQuantified(col, sqlOps[fs.Op], "all", fs.Value, bind), nil
+
+	case OpIn, OpNotIn:
+		return fmt.Sprintf("%s %s (%s)", col, sqlOps[fs.Op], bindList(fs.Value, bind)), nil
+
+	case OpIs, OpIsNot:
+		return is(col, fs)
+
+	case OpFts, OpPlFts, OpPhFts, OpWFts:
+		return fmt.Sprintf("%s @@ %s(%s)", col, sqlOps[fs.Op], bind(fs.Value)), nil
+
+	case OpBetween, OpNotBetween:
+		return between(col, fs, bind)
+
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOp, fs)
+	}
+}
+
+func (Postgres) Order(col string, o Order) string {
+	return fmt.Sprintf("%s %s %s", col, sortDirections[o.Direction], sortOptions[o.Option])
+}
+
+// Path renders `col->'a'->>'b'`, which is cast when the type is not a string,
+// e.g. `(col->>'a')::numeric`.
+func (Postgres) Path(col string, path []string, t Type) string {
+	var sb strings.Builder
+	sb.WriteString(col)
+	for i, p := range path {
+		op := "->"
+		if i == len(path)-1 {
+			op = "->>"
+		}
+
+		fmt.Fprintf(&sb, "%s'%s'", op, strings.ReplaceAll(p, "'", "''"))
+	}
+
+	cast, ok := pathCasts[strings.TrimPrefix(t.Name, "*")]
+	if !ok {
+		return sb.String()
+	}
+
+	return fmt.Sprintf("(%s)::%s", sb.String(), cast)
+}
+
+// MySQL does not support arrays, ranges and `nulls first/last`. The `ilike`
+// ops are rendered with `lower`, and full-text search with `match ...
+// against`, which requires a full-text index on the column.
+type MySQL struct{}
+
+func (MySQL) Placeholder(n int) string {
+	return "?"
+}
+
+func (MySQL) Quote(ident string) string {
+	return quoteIdent(ident, "`")
+}
+
+func (MySQL) Ops(t Type) Op {
+	if t.Array || t.JSON() {
+		return 0
+	}
+
+	return OpsComparable | OpsNull | OpsLike | OpsBetween | OpFts | OpPlFts
+}
+
+func (MySQL) Predicate(col string, fs FieldSet, bind func(any) string) (string, error) {
+	switch fs.Op {
+	case OpFts:
+		return fmt.Sprintf("match(%s) against (%s in boolean mode)", col, bind(fs.Value)), nil
+	case OpPlFts:
+		return fmt.Sprintf("match(%s) against (%s in natural language mode)", col, bind(fs.Value)), nil
+	default:
+		return predicate(col, fs, bind)
+	}
+}
+
+// Order emulates `nulls first/last`. MySQL sorts nulls first in ascending
+// order, and last in descending order, which is the opposite of the default
+// option.
+func (MySQL) Order(col string, o Order) string {
+	order := fmt.Sprintf("%s %s", col, sortDirections[o.Direction])
+	if o.Option == o.Direction.DefaultOption() {
+		return fmt.Sprintf("%s IS NULL %s, %s", col, sortNulls[o.Option], order)
+	}
+
+	return order
+}
+
+// Path renders `col->>'$.a.b'`. The values are converted implicitly when
+// compared.
+func (MySQL) Path(col string, path []string, t Type) string {
+	return jsonPath(col, path, true)
+}
+
+// SQLite does not support arrays, ranges and full-text search on regular
+// tables. The `ilike` ops are rendered with `lower`.
+type SQLite struct{}
+
+func (SQLite) Placeholder(n int) string {
+	return "?"
+}
+
+func (SQLite) Quote(ident string) string {
+	return quoteIdent(ident, `"`)
+}
+
+func (SQLite) Ops(t Type) Op {
+	if t.Array || t.JSON() {
+		return 0
+	}
+
+	return OpsComparable | OpsNull | OpsLike | OpsBetween
+}
+
+func (SQLite) Predicate(col string, fs FieldSet, bind func(any) string) (string, error) {
+	return predicate(col, fs, bind)
+}
+
+// Order uses `nulls first/last`, which is supported since SQLite 3.30.0.
+func (SQLite) Order(col string, o Order) string {
+	return Postgres{}.Order(col, o)
+}
+
+// Path renders `col->>'$.a.b'`, which is supported since SQLite 3.38.0. The
+// value is returned as the SQL type.
+func (SQLite) Path(col string, path []string, t Type) string {
+	return jsonPath(col, path, false)
+}
+
+// predicate renders the ops that are common to the dialects without arrays.
+func predicate(col string, fs FieldSet, bind func(any) string) (string, error) {
+	if _, ok := fs.Value.([]any); ok && !OpsMany.Has(fs.Op) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOp, fs)
+	}
+
+	switch fs.Op {
+	case OpEq, OpNeq, OpLt, OpLte, OpGt, OpGte:
+		return fmt.Sprintf("%s %s %s", col, sqlOps[fs.Op], bind(fs.Value)), nil
+
+	case OpLike, OpNotLike:
+		return likeJoined(col, sqlOps[fs.Op], fs.Value, bind), nil
+
+	case OpIlike:
+		return likeJoined(fmt.Sprintf("lower(%s)", col), "like", fs.Value, lower(bind)), nil
+
+	case OpNotIlike:
+		return likeJoined(fmt.Sprintf("lower(%s)", col), "not like", fs.Value, lower(bind)), nil
+
+	case OpIn, OpNotIn:
+		return fmt.Sprintf("%s %s (%s)", col, sqlOps[fs.Op], bindList(fs.Value, bind)), nil
+
+	case OpIs, OpIsNot:
+		return is(col, fs)
+
+	case OpBetween, OpNotBetween:
+		return between(col, fs, bind)
+
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOp, fs)
+	}
+}
+
+// between renders `col between ? and ?` when both bounds are inclusive.
+// Otherwise, the bounds are compared separately, e.g.
+// `(col >= ? and col < ?)`.
+func between(col string, fs FieldSet, bind func(any) string) (string, error) {
+	r, ok := fs.Value.(Range)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrBadValue, fs)
+	}
+
+	if r.Lower != nil && r.Upper != nil && r.LowerInc && r.UpperInc {
+		return fmt.Sprintf("%s %s %s and %s", col, sqlOps[fs.Op], bind(r.Lower), bind(r.Upper)), nil
+	}
+
+	var res []string
+	if r.Lower != nil {
+		op := sqlOps[OpGt]
+		if r.LowerInc {
+			op = sqlOps[OpGte]
+		}
+
+		res = append(res, fmt.Sprintf("%s %s %s", col, op, bind(r.Lower)))
+	}
+
+	if r.Upper != nil {
+		op := sqlOps[OpLt]
+		if r.UpperInc {
+			op = sqlOps[OpLte]
+		}
+
+		res = append(res, fmt.Sprintf("%s %s %s", col, op, bind(r.Upper)))
+	}
+
+	s := strings.Join(res, " and ")
+	if fs.Op.Is(OpNotBetween) {
+		return "not (" + s + ")", nil
+	}
+
+	if len(res) > 1 {
+		return "(" + s + ")", nil
+	}
+
+	return s, nil
+}
+
+// quantified renders the comparison of the array elements with the value,
+// e.g. `$1 < any(col)` for `col.anygt`. The operator is flipped, as the value
+// is on the left. The patterns are matched with `unnest`, since the pattern
+// must be on the right.
+func quantified(col string, fs FieldSet, bind func(any) string) (string, error) {
+	if _, ok := fs.Value.([]any); ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOp, fs)
+	}
+
+	switch fs.Op {
+	case OpEq, OpNeq, OpLt, OpLte, OpGt, OpGte:
+		return fmt.Sprintf("%s %s %s(%s)", bind(fs.Value), sqlOps[flippedOps[fs.Op]], fs.Quantifier, col), nil
+
+	case OpLike, OpIlike, OpNotLike, OpNotIlike:
+		match := fmt.Sprintf("v %s %s", sqlOps[fs.Op], bind(fs.Value))
+		if fs.Quantifier.Is(OpAll) {
+			return fmt.Sprintf("not exists (select 1 from unnest(%s) as e(v) where not (%s))", col, match), nil
+		}
+
+		return fmt.Sprintf("exists (select 1 from unnest(%s) as e(v) where %s)", col, match), nil
+
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedOp, fs)
+	}
+}
+
+// flippedOps are the comparison operators with the operands swapped.
+var flippedOps = map[Op]Op{
+	OpEq:  OpEq,
+	OpNeq: OpNeq,
+	OpLt:  OpGt,
+	OpLte: OpGte,
+	OpGt:  OpLt,
+	OpGte: OpLte,
+}
+
+var jsonMemberReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// jsonPath renders the JSON path in the MySQL and SQLite syntax. The members
+// are quoted, e.g. `$."dims"."1st"`, so that any key is a valid path. MySQL
+// also escapes the backslashes in the string literals.
+func jsonPath(col string, path []string, backslash bool) string {
+	members := make([]string, len(path))
+	for i, p := range path {
+		members[i] = `"` + jsonMemberReplacer.Replace(p) + `"`
+	}
+
+	lit := strings.ReplaceAll("$."+strings.Join(members, "."), "'", "''")
+	if backslash {
+		lit = strings.ReplaceAll(lit, `\`, `\\`)
+	}
+
+	return fmt.Sprintf("%s->>'%s'", col, lit)
+}
+
+// likeQuantified renders `col like $1` for a single value, and
+// `col like any(array[$1, $2])` for multiple values.
+func likeQuantified(col, op, quantifier string, value any, bind func(any) string) string {
+	vals, ok := value.([]any)
+	if !ok {
+		return fmt.Sprintf("%s %s %s", col, op, bind(value))
+	}
+
+	if len(vals) == 1 {
+		return fmt.Sprintf("%s %s %s", col, op, bind(vals[0]))
+	}
+
+	return fmt.Sprintf("%s %s %s(array[%s])", col, op, quantifier, bindList(vals, bind))
+}
+
+// likeJoined renders `col like ?` for a single value, and
+// `(col like ? or col like ?)` for multiple values. The negated ops are joined
+// with `and` instead.
+func likeJoined(col, op string, value any, bind func(any) string) string {
+	vals, ok := value.([]any)
+	if !ok {
+		return fmt.Sprintf("%s %s %s", col, op, bind(value))
+	}
+
+	if len(vals) == 1 {
+		return fmt.Sprintf("%s %s %s", col, op, bind(vals[0]))
+	}
+
+	sep := " or "
+	if strings.HasPrefix(op, "not ") {
+		sep = " and "
+	}
+
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = fmt.Sprintf("%s %s %s", col, op, bind(v))
+	}
+
+	return "(" + strings.Join(res, sep) + ")"
+}
+
+// bindArray binds a single placeholder, or an array of placeholders for array
+// fields.
+func bindArray(value any, bind func(any) string) string {
+	if vals, ok := value.([]any); ok {
+		return fmt.Sprintf("array[%s]", bindList(vals, bind))
+	}
+
+	return bind(value)
+}
+
+func bindList(value any, bind func(any) string) string {
+	vals, ok := value.([]any)
+	if !ok {
+		return bind(value)
+	}
+
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = bind(v)
+	}
+
+	return strings.Join(res, ", ")
+}
+
+func lower(bind func(any) string) func(any) string {
+	return func(v any) string {
+		return fmt.Sprintf("lower(%s)", bind(v))
+	}
+}
+
+// is renders the `is` operator, whose right-hand side cannot be parameterized.
+func is(col string, fs FieldSet) (string, error) {
+	if len(fs.Values) != 1 {
+		return "", fmt.Errorf("%w: %s", ErrBadValue, fs)
+	}
+
+	kw := strings.ToLower(fs.Values[0])
+	switch kw {
+	case "null", "true", "false", "unknown":
+		return fmt.Sprintf("%s %s %s", col, sqlOps[fs.Op], kw), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrBadValue, fs)
+	}
+}
+
+// inetOps are the Postgres containment operators of the IP addresses and
+// networks.
+var inetOps = map[Op]string{
+	OpCs: ">>=",
+	OpCd: "<<=",
+}
+
+// pathCasts is the Postgres type that the JSON path is cast to.
+var pathCasts = map[string]string{
+	"int":       "numeric",
+	"int8":      "numeric",
+	"int16":     "numeric",
+	"int32":     "numeric",
+	"int64":     "numeric",
+	"float32":   "numeric",
+	"float64":   "numeric",
+	"uint":      "numeric",
+	"uint8":     "numeric",
+	"uint16":    "numeric",
+	"uint32":    "numeric",
+	"uint64":    "numeric",
+	"decimal":   "numeric",
+	"numeric":   "numeric",
+	"date":      "date",
+	"uuid":      "uuid",
+	"inet":      "inet",
+	"cidr":      "cidr",
+	"bool":      "boolean",
+	"time.Time": "timestamptz",
+}
+
+var (
+	identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	wordRe  = regexp.MustCompile(`^\w+$`)
+)
+
+// quoteIdent quotes each part of a table-qualified identifier, e.g. `b.title`,
+// if it is not a valid identifier, e.g. `1st`, or it is a reserved keyword. The
+// case is kept as written, so `u.Bio` is folded by the database as usual.
+// Columns that are already quoted, or are expressions, are returned as it is.
+func quoteIdent(ident, quote string) string {
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		if identRe.MatchString(part) && !reservedWords[strings.ToLower(part)] {
+			continue
+		}
+
+		if !wordRe.MatchString(part) {
+			return ident
+		}
+
+		parts[i] = quote + part + quote
+	}
+
+	return strings.Join(parts, ".")
+}
+
+var reservedWords = map[string]bool{
+	"all":        true,
+	"and":        true,
+	"any":        true,
+	"as":         true,
+	"asc":        true,
+	"between":    true,
+	"by":         true,
+	"case":       true,
+	"check":      true,
+	"column":     true,
+	"constraint": true,
+	"create":     true,
+	"default":    true,
+	"desc":       true,
+	"distinct":   true,
+	"else":       true,
+	"end":        true,
+	"from":       true,
+	"group":      true,
+	"having":     true,
+	"in":         true,
+	"index":      true,
+	"is":         true,
+	"join":       true,
+	"key":        true,
+	"like":       true,
+	"limit":      true,
+	"not":        true,
+	"null":       true,
+	"offset":     true,
+	"on":         true,
+	"or":         true,
+	"order":      true,
+	"select":     true,
+	"table":      true,
+	"then":       true,
+	"to":         true,
+	"user":       true,
+	"when":       true,
+	"where":      true,
+}
+
+var sortDirections = map[SortDirection]string{
+	SortDirectionAscending:  "ASC",
+	SortDirectionDescending: "DESC",
+}
+
+var sortOptions = map[SortOption]string{
+	SortOptionNullsFirst: "NULLS FIRST",
+	SortOptionNullsLast:  "NULLS LAST",
+}
+
+// sortNulls sorts the `col is null` expression, where nulls are 1.
+var sortNulls = map[SortOption]string{
+	SortOptionNullsFirst: "DESC",
+	SortOptionNullsLast:  "ASC",
+}
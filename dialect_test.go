@@ -0,0 +1,223 @@
+package goql_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDialectQuote(t *testing.T) {
+	tests := []struct {
+		ident string
+		exp   string
+	}{
+		{"name", "name"},
+		{"u.Bio", "u.Bio"},
+		{"createdAt", "createdAt"},
+		{"order", `"order"`},
+		{"u.Order", `u."Order"`},
+		{"t.1st", `t."1st"`},
+		{`"b"."title"`, `"b"."title"`},
+		{"lower(name)", "lower(name)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ident, func(t *testing.T) {
+			if exp, got := tt.exp, (goql.Postgres{}).Quote(tt.ident); exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+		})
+	}
+}
+
+func TestDialect(t *testing.T) {
+	type User struct {
+		ID    int    `sort:"true"`
+		Name  string `sort:"true"`
+		Order string `q:"order" sort:"true"`
+		Bio   string `q:"bio,col:u.Bio"`
+	}
+
+	query := "name.ilike=a%25&name.ilike=b%25&order.neq=1&bio.eq=hello&id.in=1&id.in=2&sort_by=name.asc&sort_by=id.desc.nullslast&limit=10"
+
+	tests := []struct {
+		name    string
+		dialect goql.Dialect
+		sql     string
+	}{
+		{
+			name:    "postgres",
+			dialect: goql.Postgres{},
+			sql:     `WHERE u.Bio = $1 AND id in ($2, $3) AND name ilike any(array[$4, $5]) AND "order" <> $6 ORDER BY name ASC NULLS LAST, id DESC NULLS LAST LIMIT $7`,
+		},
+		{
+			name:    "mysql",
+			dialect: goql.MySQL{},
+			sql:     "WHERE u.Bio = ? AND id in (?, ?) AND (lower(name) like lower(?) or lower(name) like lower(?)) AND `order` <> ? ORDER BY name IS NULL ASC, name ASC, id DESC LIMIT ?",
+		},
+		{
+			name:    "sqlite",
+			dialect: goql.SQLite{},
+			sql:     `WHERE u.Bio = ? AND id in (?, ?) AND (lower(name) like lower(?) or lower(name) like lower(?)) AND "order" <> ? ORDER BY name ASC NULLS LAST, id DESC NULLS LAST LIMIT ?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := goql.NewDecoder[User]().SetDialect(tt.dialect).Decode(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, args, err := goql.NewSQLBuilder().SetDialect(tt.dialect).Build(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff([]any{"hello", 1, 2, "a%", "b%", "1", 10}, args); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDialectUnsupportedOp(t *testing.T) {
+	type Post struct {
+		Title string
+		Tags  []string `q:",type:[]string"`
+	}
+
+	tests := []struct {
+		name    string
+		dialect goql.Dialect
+		query   string
+	}{
+		{"mysql array", goql.MySQL{}, "tags.cs=go"},
+		{"mysql phrase fts", goql.MySQL{}, "title.phfts=go"},
+		{"sqlite fts", goql.SQLite{}, "title.fts=go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = goql.NewDecoder[Post]().SetDialect(tt.dialect).Decode(v)
+			if !errors.Is(err, goql.ErrUnsupportedOp) {
+				t.Fatalf("expected %v, got %v", goql.ErrUnsupportedOp, err)
+			}
+		})
+	}
+
+	t.Run("postgres", func(t *testing.T) {
+		v, err := url.ParseQuery("tags.cs=go&title.phfts=go")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = goql.NewDecoder[Post]().Decode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestDialectJSONPath(t *testing.T) {
+	type Product struct {
+		Attrs map[string]int
+	}
+
+	tests := []struct {
+		name    string
+		dialect goql.Dialect
+		sql     string
+	}{
+		{"postgres", goql.Postgres{}, `WHERE (attrs->'dims'->>'width')::numeric > $1`},
+		{"mysql", goql.MySQL{}, `WHERE attrs->>'$."dims"."width"' > ?`},
+		{"sqlite", goql.SQLite{}, `WHERE attrs->>'$."dims"."width"' > ?`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := goql.NewDecoder[Product]().SetDialect(tt.dialect)
+
+			f, err := dec.DecodeString("attrs.dims.width.gt=10")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, args, err := goql.NewSQLBuilder().SetDialect(tt.dialect).Build(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff([]any{10}, args); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+
+			_, err = dec.DecodeString("attrs.haskey=dims")
+			if exp, got := tt.name != "postgres", errors.Is(err, goql.ErrUnsupportedOp); exp != got {
+				t.Fatalf("expected %v, got %v", exp, err)
+			}
+		})
+	}
+}
+
+func TestDialectPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect goql.PathDialect
+		path    []string
+		exp     string
+	}{
+		{"mysql digit", goql.MySQL{}, []string{"1st"}, `attrs->>'$."1st"'`},
+		{"mysql escape", goql.MySQL{}, []string{`it's`, `a\b`}, `attrs->>'$."it''s"."a\\\\b"'`},
+		{"sqlite escape", goql.SQLite{}, []string{`it's`, `a\b`}, `attrs->>'$."it''s"."a\\b"'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if exp, got := tt.exp, tt.dialect.Path("attrs", tt.path, goql.Type{Name: "string"}); exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		type Product struct {
+			Attrs map[string]int
+		}
+
+		// The embedded interface does not promote the Path method.
+		type noPath struct{ goql.Dialect }
+
+		dec := goql.NewDecoder[Product]().SetDialect(noPath{goql.Postgres{}})
+
+		_, err := dec.DecodeString("attrs.size.gt=10")
+		if !errors.Is(err, goql.ErrUnsupportedOp) {
+			t.Fatalf("expected %v, got %v", goql.ErrUnsupportedOp, err)
+		}
+
+		if _, err := dec.DecodeString("attrs.haskey=size"); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package goql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder encodes a Filter back to url.Values, which can be decoded again by
+// the Decoder.
+type Encoder struct {
+	querySort   string
+	queryLimit  string
+	queryOffset string
+}
+
+// NewEncoder returns an Encoder with the default query string names.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		querySort:   QuerySort,
+		queryLimit:  QueryLimit,
+		queryOffset: QueryOffset,
+	}
+}
+
+// Encoder returns an Encoder with the same query string names as the Decoder.
+func (d *Decoder[T]) Encoder() *Encoder {
+	return &Encoder{
+		querySort:   d.querySort,
+		queryLimit:  d.queryLimit,
+		queryOffset: d.queryOffset,
+	}
+}
+
+func (e *Encoder) SetQuerySortName(name string) *Encoder {
+	if name == "" {
+		panic("goql: query sort name cannot be empty")
+	}
+
+	e.querySort = name
+
+	return e
+}
+
+func (e *Encoder) SetQueryLimitName(name string) *Encoder {
+	if name == "" {
+		panic("goql: query limit name cannot be empty")
+	}
+
+	e.queryLimit = name
+
+	return e
+}
+
+func (e *Encoder) SetQueryOffsetName(name string) *Encoder {
+	if name == "" {
+		panic("goql: query offset name cannot be empty")
+	}
+
+	e.queryOffset = name
+
+	return e
+}
+
+// Encode encodes the filter to url.Values. The top-level `And` field sets are
+// encoded as `field.op=value`, and the nested conjunctions as
+// `and=or.(field.op:value,...)`. The Scope is not encoded, as it is merged
+// again when decoding.
+func (e *Encoder) Encode(f *Filter) url.Values {
+	u := make(url.Values)
+
+	// The scope is merged into the `And` by the decoder, and is not part of
+	// the query string.
+	ands := f.And
+	if n := len(f.Scope); n > 0 && n <= len(ands) {
+		ands = ands[n:]
+	}
+
+	for _, fs := range ands {
+		switch fs.Op {
+		case OpAnd, OpOr:
+			u.Add(QueryAnd, EncodeFieldSet(fs)[0])
+		default:
+			key := fieldKey(fs)
+			u[key] = append(u[key], FormatValues(fs)...)
+		}
+	}
+
+	for _, fs := range f.Or {
+		u[QueryOr] = append(u[QueryOr], EncodeFieldSet(fs)...)
+	}
+
+	for _, o := range f.Sort {
+		u.Add(e.querySort, o.Query())
+	}
+
+	if f.Limit != nil {
+		u.Set(e.queryLimit, strconv.Itoa(*f.Limit))
+	}
+
+	if f.Offset != nil {
+		u.Set(e.queryOffset, strconv.Itoa(*f.Offset))
+	}
+
+	return u
+}
+
+// EncodeFieldSet encodes the field set to the conjunction syntax, e.g.
+// `name.eq:john` or `or.(name.eq:john,age.gt:10)`. Field sets with multiple
+// values are encoded once per value.
+func EncodeFieldSet(fs FieldSet) []string {
+	var children []FieldSet
+	switch fs.Op {
+	case OpAnd:
+		children = fs.And
+	case OpOr:
+		children = fs.Or
+	default:
+		vals := FormatValues(fs)
+		res := make([]string, len(vals))
+		for i, v := range vals {
+			if _, ok := Unquote(v, '"', '"'); !ok && strings.Contains(v, ",") {
+				v = fmt.Sprintf("%q", v)
+			}
+
+			res[i] = fmt.Sprintf("%s:%s", fieldKey(fs), v)
+		}
+
+		return res
+	}
+
+	items := make([]string, 0, len(children))
+	for _, child := range children {
+		items = append(items, EncodeFieldSet(child)...)
+	}
+
+	if fs.Negated {
+		return []string{fmt.Sprintf("%s.%s.(%s)", OpNot, fs.Op, strings.Join(items, ","))}
+	}
+
+	return []string{fmt.Sprintf("%s.(%s)", fs.Op, strings.Join(items, ","))}
+}
+
+// fieldKey returns the query string key of the field set, e.g. `age.gt` or
+// `age.not.gt`.
+func fieldKey(fs FieldSet) string {
+	name := strings.Join(append([]string{fs.Name}, fs.Path...), ".")
+	if fs.Len {
+		name = fmt.Sprintf("%s.%s", name, OpLen)
+	}
+
+	op := fs.Quantifier.String() + fs.Op.String()
+	if fs.Negated {
+		return fmt.Sprintf("%s.%s.%s", name, OpNot, op)
+	}
+
+	return fmt.Sprintf("%s.%s", name, op)
+}
+
+// FormatValues returns the query string values of the field set. The raw
+// values are returned if the field set is decoded, otherwise the value is
+// formatted. The range of a single date, e.g. `created_at.eq=2006-01-02`, is
+// formatted as the range literal.
+func FormatValues(fs FieldSet) []string {
+	if r, ok := fs.Value.(Range); ok && OpsBetween.Has(fs.Op) {
+		if _, err := ParseRange(fs.Values, ParseString); err != nil {
+			return []string{r.String()}
+		}
+	}
+
+	if len(fs.Values) > 0 {
+		return fs.Values
+	}
+
+	if vals, ok := fs.Value.([]any); ok {
+		res := make([]string, len(vals))
+		for i, v := range vals {
+			res[i] = FormatValue(v)
+		}
+
+		return res
+	}
+
+	return []string{FormatValue(fs.Value)}
+}
+
+// FormatValue formats the value to the query string value, which can be
+// parsed again by the parsers.
+func FormatValue(v any) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "null"
+		}
+
+		return FormatValue(rv.Elem().Interface())
+	}
+
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case json.RawMessage:
+		return string(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,155 @@
+package goql_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncoderRoundTrip(t *testing.T) {
+	type User struct {
+		Name   string `sort:"true"`
+		Age    int
+		Height *int
+	}
+
+	dec := goql.NewDecoder[User]().
+		SetQuerySortName("_sort_by").
+		SetQueryLimitName("_limit").
+		SetQueryOffsetName("_offset")
+
+	v := make(url.Values)
+	v.Set("name.eq", "john")
+	v.Add("name.in", "alice")
+	v.Add("name.in", "bob, jr")
+	v.Add("and", "not.or.(age.not.lt:1,name.eq:x)")
+	v.Add("and", `or.(age.gt:10,name.eq:"a,b")`)
+	v.Set("age.not.in", "3")
+	v.Add("or", "height.isnot:null")
+	v.Add("or", "and.(height.gte:170,height.isnot:null)")
+	v.Add("_sort_by", "name.desc.nullslast")
+	v.Set("_limit", "10")
+	v.Set("_offset", "5")
+
+	f, err := dec.Decode(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u := dec.Encoder().Encode(f)
+	if diff := cmp.Diff(v, u); diff != "" {
+		t.Fatalf("exp+, got-: %s", diff)
+	}
+
+	got, err := dec.Decode(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(f, got); diff != "" {
+		t.Fatalf("exp+, got-: %s", diff)
+	}
+}
+
+func TestEncoderFieldSet(t *testing.T) {
+	height := 170
+	limit := 20
+
+	f := &goql.Filter{
+		And: []goql.FieldSet{
+			{Name: "name", Op: goql.OpIn, Value: []any{"alice", "bob, jr"}},
+			{Name: "created_at", Op: goql.OpGt, Value: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		Or: []goql.FieldSet{
+			{Op: goql.OpAnd, And: []goql.FieldSet{
+				{Name: "height", Op: goql.OpGte, Value: &height},
+				{Name: "name", Op: goql.OpIn, Value: []any{"alice", "bob, jr"}},
+			}},
+		},
+		Sort: []goql.Order{
+			{Field: "name", Direction: goql.SortDirectionAscending, Option: goql.SortOptionNullsLast},
+		},
+		Limit: &limit,
+	}
+
+	exp := url.Values{
+		"name.in":       []string{"alice", "bob, jr"},
+		"created_at.gt": []string{"2022-01-01T00:00:00Z"},
+		"or":            []string{`and.(height.gte:170,name.in:alice,name.in:"bob, jr")`},
+		"sort_by":       []string{"name.asc.nullslast"},
+		"limit":         []string{"20"},
+	}
+
+	if diff := cmp.Diff(exp, goql.NewEncoder().Encode(f)); diff != "" {
+		t.Fatalf("exp+, got-: %s", diff)
+	}
+}
+
+func TestEncoderScope(t *testing.T) {
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	dec := goql.NewDecoder[User]().
+		SetScope(goql.FieldSet{Name: "deleted_at", Op: goql.OpIs, Value: nil, Values: []string{"null"}}).
+		SetScopeFunc(func(ctx context.Context, f *goql.Filter) error {
+			f.And = append(f.And, goql.FieldSet{Name: "tenant_id", Op: goql.OpEq, Value: 42, Values: []string{"42"}})
+
+			return nil
+		})
+
+	tests := []struct {
+		name  string
+		query string
+		exp   url.Values
+		sql   string
+	}{
+		{
+			name:  "and",
+			query: "name.eq=john&age.gt=10",
+			exp:   url.Values{"name.eq": {"john"}, "age.gt": {"10"}},
+			sql:   "WHERE deleted_at is null AND tenant_id = $1 AND age > $2 AND name = $3",
+		},
+		{
+			name:  "or is wrapped",
+			query: "name.eq=john&or=age.gt:10&or=age.lt:5",
+			exp:   url.Values{"and": {"or.(name.eq:john,age.gt:10,age.lt:5)"}},
+			sql:   "WHERE deleted_at is null AND tenant_id = $1 AND (age > $2 OR age < $3 OR name = $4)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			u := dec.Encoder().Encode(f)
+			if diff := cmp.Diff(tt.exp, u); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+
+			// The scope is merged once when decoding again.
+			got, err := dec.Decode(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, _, err := goql.BuildSQL(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp := tt.sql; exp != sql {
+				t.Fatalf("expected %q, got %q", exp, sql)
+			}
+
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package goql
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DecodeError is returned when decoding the query string fails. It wraps the
+// sentinel errors, e.g. ErrUnknownField, as well as the errors returned by the
+// parsers.
+type DecodeError struct {
+	// Key is the query string key, e.g. `age.gt`, `and` or `sort_by`.
+	Key string
+
+	// Path is the path of the nested conjunctions within the key, e.g.
+	// `and=or.(and.(age.gt:10))` has the path `[or and]`.
+	Path []string
+
+	Field string
+	Op    Op
+
+	// Value is the raw value that fails decoding.
+	Value string
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	loc := strings.Join(append([]string{e.Key}, e.Path...), ".")
+	if loc == "" && e.Field == "" {
+		return e.Err.Error()
+	}
+
+	if e.Field == "" || strings.HasPrefix(e.Key, e.Field+".") {
+		return fmt.Sprintf("%s: %s", loc, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", loc, e.Field, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeError returns the error as a DecodeError.
+func decodeError(err error) *DecodeError {
+	if de, ok := err.(*DecodeError); ok {
+		return de
+	}
+
+	return &DecodeError{Err: err}
+}
+
+// validationError wraps the error of the `Validate() error` method, and is
+// also ErrBadValue.
+type validationError struct {
+	err error
+}
+
+func (e *validationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrBadValue, e.err)
+}
+
+func (e *validationError) Is(target error) bool {
+	return target == ErrBadValue
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
+}
+
+// DecodeErrors is the list of errors returned when decoding with
+// SetCollectErrors.
+type DecodeErrors []*DecodeError
+
+func (errs DecodeErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Is returns true if any of the errors matches the target.
+func (errs DecodeErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error that matches the target.
+func (errs DecodeErrors) As(target any) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paramOrder is the order of the query string parameters.
+type paramOrder []param
+
+type param struct {
+	key   string
+	value string
+}
+
+// valuesOrder returns the order of url.Values, which is sorted by key, the same
+// as url.Values.Encode.
+func valuesOrder(u url.Values) paramOrder {
+	keys := make([]string, 0, len(u))
+	for key := range u {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var res paramOrder
+	for _, key := range keys {
+		for _, value := range u[key] {
+			res = append(res, param{key, value})
+		}
+	}
+
+	return res
+}
+
+// rawQueryOrder returns the order of the parameters in the raw query string.
+func rawQueryOrder(rawQuery string) paramOrder {
+	var res paramOrder
+	for _, kv := range strings.Split(rawQuery, "&") {
+		k, v := Split2(kv, "=")
+		key, err := url.QueryUnescape(k)
+		if err != nil {
+			continue
+		}
+
+		value, err := url.QueryUnescape(v)
+		if err != nil {
+			continue
+		}
+
+		res = append(res, param{key, value})
+	}
+
+	return res
+}
+
+// sort sorts the errors by the parameter they originate from. Errors from
+// nested conjunctions matches the `and` or `or` parameter that contains the
+// value. Otherwise, the first parameter of the key is used.
+func (o paramOrder) sort(errs DecodeErrors) {
+	nested := func(de *DecodeError) bool {
+		return len(de.Path) > 0 || de.Key == QueryAnd || de.Key == QueryOr
+	}
+
+	pos := func(de *DecodeError) int {
+		first := len(o)
+		for i, p := range o {
+			if p.key != de.Key {
+				continue
+			}
+
+			if p.value == de.Value {
+				return i
+			}
+
+			if de.Value != "" && nested(de) && strings.Contains(p.value, de.Value) {
+				return i
+			}
+
+			if i < first {
+				first = i
+			}
+		}
+
+		return first
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return pos(errs[i]) < pos(errs[j])
+	})
+}
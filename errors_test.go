@@ -0,0 +1,237 @@
+package goql_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestDecodeError(t *testing.T) {
+	type User struct {
+		Name      string `sort:"true"`
+		Age       int
+		CreatedAt time.Time
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		exp   goql.DecodeError
+		err   error
+	}{
+		{
+			name:  "unknown field",
+			query: "email.eq=john",
+			exp:   goql.DecodeError{Key: "email.eq", Field: "email", Op: goql.OpEq, Value: "john"},
+			err:   goql.ErrUnknownField,
+		},
+		{
+			name:  "unknown op",
+			query: "name.foo=john",
+			exp:   goql.DecodeError{Key: "name.foo", Field: "name", Value: "john"},
+			err:   goql.ErrUnknownOperator,
+		},
+		{
+			name:  "too many values",
+			query: "age.eq=1&age.eq=2",
+			exp:   goql.DecodeError{Key: "age.eq", Field: "age", Op: goql.OpEq, Value: "2"},
+			err:   goql.ErrTooManyValues,
+		},
+		{
+			name:  "bad value",
+			query: "age.eq=one",
+			exp:   goql.DecodeError{Key: "age.eq", Field: "age", Op: goql.OpEq, Value: "one"},
+			err:   goql.ErrBadValue,
+		},
+		{
+			name:  "bad value in and",
+			query: "and=age.gt:one",
+			exp:   goql.DecodeError{Key: "and", Field: "age", Op: goql.OpGt, Value: "one"},
+			err:   goql.ErrBadValue,
+		},
+		{
+			name:  "unknown field in nested conjunction",
+			query: "or=and.(name.eq:john,or.(created_at.gt:yesterday))",
+			exp: goql.DecodeError{
+				Key:   "or",
+				Path:  []string{"and", "or"},
+				Field: "created_at",
+				Op:    goql.OpGt,
+				Value: "yesterday",
+			},
+			err: goql.ErrUnknownField,
+		},
+		{
+			name:  "invalid conjunction",
+			query: "and=or.name.eq:john",
+			exp:   goql.DecodeError{Key: "and", Path: []string{"or"}, Op: goql.OpOr, Value: "or.name.eq:john"},
+			err:   goql.ErrInvalidConjunction,
+		},
+		{
+			name:  "bad limit",
+			query: "limit=ten",
+			exp:   goql.DecodeError{Key: "limit", Value: "ten"},
+			err:   goql.ErrBadValue,
+		},
+		{
+			name:  "bad sort",
+			query: "sort_by=name.up",
+			exp:   goql.DecodeError{Key: "sort_by", Value: "name.up"},
+			err:   goql.ErrInvalidSortDirection,
+		},
+	}
+
+	dec := goql.NewDecoder[User]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = dec.Decode(v)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			var de *goql.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %T", err)
+			}
+
+			if diff := cmp.Diff(tt.exp, *de, cmpopts.IgnoreFields(goql.DecodeError{}, "Err")); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+
+			t.Log(err)
+		})
+	}
+}
+
+func TestDecodeErrorParser(t *testing.T) {
+	type User struct {
+		CreatedAt time.Time `q:"created_at"`
+	}
+
+	v := make(url.Values)
+	v.Add("or", "and.(created_at.gt:yesterday)")
+
+	_, err := goql.NewDecoder[User]().Decode(v)
+	if !errors.Is(err, goql.ErrBadValue) {
+		t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+	}
+
+	var de *goql.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %T", err)
+	}
+
+	if exp, got := "created_at", de.Field; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	if exp, got := `or.and: created_at: goql: bad value: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`, err.Error(); exp != got {
+		t.Fatalf("expected %q, got %q", exp, got)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type User struct {
+		Name string `sort:"true"`
+		Age  int
+	}
+
+	dec := goql.NewDecoder[User]().SetCollectErrors(true)
+
+	t.Run("raw query order", func(t *testing.T) {
+		_, err := dec.DecodeString("name.foo=x&limit=ten&age.eq=one&sort_by=name.up&and=or.(age.gt:bad,email.eq:x)")
+
+		var errs goql.DecodeErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("expected DecodeErrors, got %T", err)
+		}
+
+		keys := make([]string, len(errs))
+		for i, de := range errs {
+			keys[i] = de.Key
+		}
+
+		if diff := cmp.Diff([]string{"name.foo", "limit", "age.eq", "sort_by", "and", "and"}, keys); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+
+		for _, target := range []error{
+			goql.ErrUnknownOperator,
+			goql.ErrBadValue,
+			goql.ErrInvalidSortDirection,
+			goql.ErrUnknownField,
+		} {
+			if !errors.Is(err, target) {
+				t.Fatalf("expected %v, got %v", target, err)
+			}
+		}
+
+		t.Log(err)
+	})
+
+	t.Run("values order", func(t *testing.T) {
+		v := make(url.Values)
+		v.Set("offset", "x")
+		v.Add("age.in", "1")
+		v.Add("age.in", "two")
+		v.Add("age.in", "three")
+
+		_, err := dec.Decode(v)
+
+		var errs goql.DecodeErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("expected DecodeErrors, got %T", err)
+		}
+
+		values := make([]string, len(errs))
+		for i, de := range errs {
+			values[i] = de.Value
+		}
+
+		if diff := cmp.Diff([]string{"two", "three", "x"}, values); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+	})
+
+	t.Run("stops at first error", func(t *testing.T) {
+		var n int
+		dec := goql.NewDecoder[User]().SetParser("int", func(in string) (any, error) {
+			n++
+
+			return goql.ParseInt(in)
+		})
+
+		_, err := dec.DecodeString("age.eq=one&age.gt=two&and=age.lt:three")
+		if !errors.Is(err, goql.ErrBadValue) {
+			t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+		}
+
+		if exp, got := 1, n; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("first error", func(t *testing.T) {
+		_, err := goql.NewDecoder[User]().DecodeString("limit=ten&age.eq=one")
+
+		var de *goql.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected DecodeError, got %T", err)
+		}
+
+		if exp, got := "limit", de.Key; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+}
@@ -9,14 +9,14 @@ import (
 	"github.com/alextanhongpin/goql"
 )
 
-// type alias needs to be registered manually
+// Named types are parsed with the parser of their kind, e.g. string.
 type Hobby string
 
 type User struct {
 	Name      string `sort:"true"`
 	Age       int    `sort:"true"`
 	Married   *bool
-	Hobbies   []Hobby `q:"hobbies,type:[]string"`
+	Hobbies   []Hobby `q:"hobbies"`
 	Birthday  *time.Time
 	MarriedAt time.Time
 	Height    *int
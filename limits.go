@@ -0,0 +1,82 @@
+package goql
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTooDeep           = errors.New("goql: too deep")
+	ErrTooManyPredicates = errors.New("goql: too many predicates")
+	ErrTooManyFields     = errors.New("goql: too many fields")
+	ErrValueTooLong      = errors.New("goql: value too long")
+)
+
+// Limits restricts the complexity of the query string. Zero means no limit.
+type Limits struct {
+	// Depth is the maximum nesting of the conjunctions, e.g.
+	// `or=and.(age.gt:10)` has the depth 1.
+	Depth int
+
+	// Predicates is the maximum number of predicates, including those in the
+	// conjunctions.
+	Predicates int
+
+	// Values is the maximum number of values of the multi-value ops, e.g.
+	// `id.in=1&id.in=2`. Fails with ErrTooManyValues.
+	Values int
+
+	// ValueLength is the maximum length of the raw values, including the
+	// `and` and `or` groups before they are split.
+	ValueLength int
+
+	// Fields is the maximum number of distinct fields.
+	Fields int
+}
+
+// SetLimits sets the complexity limits of the query string. Each limit fails
+// with its own error, e.g. ErrTooDeep.
+func (d *Decoder[T]) SetLimits(limits Limits) *Decoder[T] {
+	if err := d.setLimits(limits); err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// counter counts the predicates and the distinct fields as they are decoded,
+// so that the limits also bound the work of decoding.
+type counter struct {
+	limits     Limits
+	predicates int
+	fields     map[string]bool
+
+	// err is the first limit that is exceeded.
+	err error
+}
+
+func newCounter(limits Limits) *counter {
+	return &counter{
+		limits: limits,
+		fields: make(map[string]bool),
+	}
+}
+
+// add counts the predicate of the field, and fails when the limits are
+// exceeded.
+func (c *counter) add(field string) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	c.predicates++
+	c.fields[field] = true
+
+	if max := c.limits.Predicates; max > 0 && c.predicates > max {
+		c.err = fmt.Errorf("%w: max %d", ErrTooManyPredicates, max)
+	} else if max := c.limits.Fields; max > 0 && len(c.fields) > max {
+		c.err = fmt.Errorf("%w: max %d", ErrTooManyFields, max)
+	}
+
+	return c.err
+}
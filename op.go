@@ -30,11 +30,29 @@ const (
 	OpsRange = OpCs | OpCd | OpOv | OpSl | OpSr | OpNxr | OpNxl | OpAdj
 
 	// OpsMany operators supports multiple values.
-	OpsMany = OpsIn | OpsLike
+	OpsMany = OpsIn | OpsLike | OpHasAnyKeys | OpHasAllKeys
+
+	// OpsQuantifier represents the quantifiers of the array elements, which
+	// prefixes the operator, e.g. `scores.anygt=50`.
+	OpsQuantifier = OpAny | OpAll
+
+	// OpsQuantifiable operators can be quantified.
+	OpsQuantifiable = OpEq | OpNeq | OpLt | OpLte | OpGt | OpGte | OpsLike
+
+	// OpsJSON represents the JSON key-existence and containment operators.
+	OpsJSON = OpHasKey | OpHasAnyKeys | OpHasAllKeys | OpCs | OpCd
+
+	// OpsBetween represents the variation of `between`.
+	OpsBetween = OpBetween | OpNotBetween
+
+	// OpsInet represents the containment operators of the IP addresses and
+	// networks, e.g. `ip.cd=10.0.0.0/8` is `ip <<= '10.0.0.0/8'`.
+	OpsInet = OpCs | OpCd | OpOv | OpSl | OpSr
 )
 
-// Op represents a SQL operator.
-type Op int
+// Op represents a SQL operator. The ops are bit flags beyond 32 bits, so that
+// the type is uint64 for the 32-bit platforms, see `make build32`.
+type Op uint64
 
 func (op Op) String() string {
 	return opsText[op]
@@ -61,35 +79,43 @@ func (op Op) Is(tgt Op) bool {
 // range operators:  https://www.postgresql.org/docs/14/functions-range.html
 // array operators: https://www.postgresql.org/docs/current/functions-array.html
 const (
-	OpEq       Op = 1 << iota // =, equals, e.g. name.eq=john appleseed
-	OpNeq                     // <> or !=, not equals, e.g. name.neq=john appleseed
-	OpLt                      // <, less than
-	OpLte                     // <=, less than equals
-	OpGt                      // >, greater than
-	OpGte                     // >=, greater than equals
-	OpLike                    // like, multi-values, e.g. name.like=john*
-	OpIlike                   // ilike, multi-values, same as like, but case insensitive, e.g. name.ilike=john%
-	OpNotLike                 // not like, multi-values, e.g. name.notlike=john*
-	OpNotIlike                // not ilike, multi-values, e.g. name.notilike=john*
-	OpIn                      // in, multi-values, name.in=alice&name.in=bob
-	OpNotIn                   // not in, multi-values, name.notin=alice&name.notin=bob
-	OpIs                      // is, checking for exact equality (null,true,false,unknown), e.g. age.is=null
-	OpIsNot                   // is not, e.g. age.isnot=null
-	OpFts                     // Full-Text search using to_tsquery
-	OpPlFts                   // Full-Text search using plain to tsquery
-	OpPhFts                   // Full-Text search using phrase to tsquery
-	OpWFts                    // Full-Text search using word.
-	OpCs                      // @>, contains, e.g. ?tags.cs=apple&tags.cs=orange
-	OpCd                      // <@, contained in e.g. ?values.cd=1&values.cd=2
-	OpOv                      // &&, overlap
-	OpSl                      // <<, strictly left of
-	OpSr                      // >>, strictly right of
-	OpNxr                     // &<
-	OpNxl                     // &>
-	OpAdj                     // -|-
-	OpNot                     // not
-	OpOr                      // or, e.g. or=(age.gt:10,age.lt:100)
-	OpAnd                     // and, e.g. and=(or.(married_at.isnot:null, married_at.gt:now))
+	OpEq         Op = 1 << iota // =, equals, e.g. name.eq=john appleseed
+	OpNeq                       // <> or !=, not equals, e.g. name.neq=john appleseed
+	OpLt                        // <, less than
+	OpLte                       // <=, less than equals
+	OpGt                        // >, greater than
+	OpGte                       // >=, greater than equals
+	OpLike                      // like, multi-values, e.g. name.like=john*
+	OpIlike                     // ilike, multi-values, same as like, but case insensitive, e.g. name.ilike=john%
+	OpNotLike                   // not like, multi-values, e.g. name.notlike=john*
+	OpNotIlike                  // not ilike, multi-values, e.g. name.notilike=john*
+	OpIn                        // in, multi-values, name.in=alice&name.in=bob
+	OpNotIn                     // not in, multi-values, name.notin=alice&name.notin=bob
+	OpIs                        // is, checking for exact equality (null,true,false,unknown), e.g. age.is=null
+	OpIsNot                     // is not, e.g. age.isnot=null
+	OpFts                       // Full-Text search using to_tsquery
+	OpPlFts                     // Full-Text search using plain to tsquery
+	OpPhFts                     // Full-Text search using phrase to tsquery
+	OpWFts                      // Full-Text search using word.
+	OpCs                        // @>, contains, e.g. ?tags.cs=apple&tags.cs=orange
+	OpCd                        // <@, contained in e.g. ?values.cd=1&values.cd=2
+	OpOv                        // &&, overlap
+	OpSl                        // <<, strictly left of
+	OpSr                        // >>, strictly right of
+	OpNxr                       // &<
+	OpNxl                       // &>
+	OpAdj                       // -|-
+	OpNot                       // not
+	OpOr                        // or, e.g. or=(age.gt:10,age.lt:100)
+	OpAnd                       // and, e.g. and=(or.(married_at.isnot:null, married_at.gt:now))
+	OpBetween                   // between, e.g. age.between=10,20 or age.between=[10,20)
+	OpNotBetween                // not between, e.g. age.notbetween=10,20
+	OpHasKey                    // ?, the JSON key exists, e.g. attrs.haskey=color
+	OpHasAnyKeys                // ?|, any of the JSON keys exists, multi-values, e.g. attrs.hasanykeys=color&attrs.hasanykeys=size
+	OpHasAllKeys                // ?&, all of the JSON keys exists, multi-values, e.g. attrs.hasallkeys=color&attrs.hasallkeys=size
+	OpAny                       // any, any of the array elements, e.g. scores.anygt=50
+	OpAll                       // all, all of the array elements, e.g. tags.alllike=go%
+	OpLen                       // len, the cardinality of the array, opt-in, e.g. tags.len.gt=3
 )
 
 func ParseOp(unk string) (Op, bool) {
@@ -98,33 +124,41 @@ func ParseOp(unk string) (Op, bool) {
 }
 
 var opsText = map[Op]string{
-	OpEq:       "eq",
-	OpNeq:      "neq",
-	OpLt:       "lt",
-	OpLte:      "lte",
-	OpGt:       "gt",
-	OpGte:      "gte",
-	OpLike:     "like",
-	OpIlike:    "ilike",
-	OpNotLike:  "notlike",
-	OpNotIlike: "notilike",
-	OpIn:       "in",
-	OpNotIn:    "notin",
-	OpIs:       "is",
-	OpIsNot:    "isnot",
-	OpFts:      "fts",
-	OpPlFts:    "plfts",
-	OpPhFts:    "phfts",
-	OpWFts:     "wfts",
-	OpCs:       "cs",
-	OpCd:       "cd",
-	OpOv:       "ov",
-	OpSl:       "sl",
-	OpSr:       "sr",
-	OpNxr:      "nxr",
-	OpNxl:      "nxl",
-	OpAdj:      "adj",
-	OpNot:      "not",
-	OpOr:       "or",
-	OpAnd:      "and",
+	OpEq:         "eq",
+	OpNeq:        "neq",
+	OpLt:         "lt",
+	OpLte:        "lte",
+	OpGt:         "gt",
+	OpGte:        "gte",
+	OpLike:       "like",
+	OpIlike:      "ilike",
+	OpNotLike:    "notlike",
+	OpNotIlike:   "notilike",
+	OpIn:         "in",
+	OpNotIn:      "notin",
+	OpIs:         "is",
+	OpIsNot:      "isnot",
+	OpFts:        "fts",
+	OpPlFts:      "plfts",
+	OpPhFts:      "phfts",
+	OpWFts:       "wfts",
+	OpCs:         "cs",
+	OpCd:         "cd",
+	OpOv:         "ov",
+	OpSl:         "sl",
+	OpSr:         "sr",
+	OpNxr:        "nxr",
+	OpNxl:        "nxl",
+	OpAdj:        "adj",
+	OpNot:        "not",
+	OpOr:         "or",
+	OpAnd:        "and",
+	OpBetween:    "between",
+	OpNotBetween: "notbetween",
+	OpHasKey:     "haskey",
+	OpHasAnyKeys: "hasanykeys",
+	OpHasAllKeys: "hasallkeys",
+	OpAny:        "any",
+	OpAll:        "all",
+	OpLen:        "len",
 }
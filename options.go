@@ -0,0 +1,537 @@
+package goql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrInvalidOption = errors.New("goql: invalid option")
+
+// config is the configuration of the Decoder, which is independent of the
+// decoded type.
+type config struct {
+	// value is the zero value of the decoded type, which is parsed when the
+	// tags changes.
+	value any
+
+	tags    map[string]*Tag
+	parsers map[string]ParserFn
+
+	// typeParsers are the parsers by the Go type, see RegisterParser.
+	typeParsers map[reflect.Type]ParserFn
+
+	// types are the Go types of the fields, see fieldTypes.
+	types map[string]reflect.Type
+
+	// ops and columns are the overrides of SetOps and SetColumn, which are
+	// applied again when the tags are parsed, see parseTags.
+	ops     map[string]Op
+	columns map[string]string
+
+	sortTag     string
+	filterTag   string
+	limitMin    int
+	limitMax    int
+	querySort   string
+	queryLimit  string
+	queryOffset string
+	queryAfter  string
+	queryBefore string
+	dialect     Dialect
+
+	collectErrors bool
+
+	scope   []FieldSet
+	scopeFn ScopeFunc
+
+	defaultSort []Order
+	strictSort  bool
+	sortMax     int
+
+	reserved      []string
+	ignoreKeys    []string
+	ignoreUnknown bool
+
+	limits Limits
+
+	timeFormat TimeFormat
+
+	// compiled is true for the copy of CompiledDecoder, which is validated
+	// once.
+	compiled bool
+}
+
+// Option configures the decoder, see NewDecoderE. Each option is the same as
+// the Set* method of the Decoder, but returns the error instead of panicking.
+type Option func(c *config) error
+
+func WithFilterTag(filterTag string) Option {
+	return func(c *config) error {
+		return c.setFilterTag(filterTag)
+	}
+}
+
+func WithSortTag(sortTag string) Option {
+	return func(c *config) error {
+		return c.setSortTag(sortTag)
+	}
+}
+
+func WithLimitRange(min, max int) Option {
+	return func(c *config) error {
+		return c.setLimitRange(min, max)
+	}
+}
+
+func WithParsers(parsers map[string]ParserFn) Option {
+	return func(c *config) error {
+		return c.setParsers(parsers)
+	}
+}
+
+func WithParser(name string, parserFn ParserFn) Option {
+	return func(c *config) error {
+		return c.setParser(name, parserFn)
+	}
+}
+
+// WithTypeParser is the option of RegisterParser.
+func WithTypeParser[V any](fn func(string) (V, error)) Option {
+	return func(c *config) error {
+		if fn == nil {
+			return fmt.Errorf("%w: parser cannot be nil", ErrInvalidOption)
+		}
+
+		c.setTypeParser(reflect.TypeOf((*V)(nil)).Elem(), func(in string) (any, error) {
+			v, err := fn(in)
+			if err != nil {
+				return nil, err
+			}
+
+			return v, nil
+		})
+
+		return nil
+	}
+}
+
+func WithOps(field string, ops Op) Option {
+	return func(c *config) error {
+		return c.setOps(field, ops)
+	}
+}
+
+func WithColumn(field, column string) Option {
+	return func(c *config) error {
+		return c.setColumn(field, column)
+	}
+}
+
+func WithDialect(dialect Dialect) Option {
+	return func(c *config) error {
+		return c.setDialect(dialect)
+	}
+}
+
+func WithReservedKeys(keys ...string) Option {
+	return func(c *config) error {
+		return c.setReservedKeys(keys...)
+	}
+}
+
+func WithIgnoreKeys(keys ...string) Option {
+	return func(c *config) error {
+		return c.setIgnoreKeys(keys...)
+	}
+}
+
+func WithIgnoreUnknown(ignore bool) Option {
+	return func(c *config) error {
+		c.ignoreUnknown = ignore
+
+		return nil
+	}
+}
+
+func WithQuerySortName(name string) Option {
+	return func(c *config) error {
+		return c.setQuerySortName(name)
+	}
+}
+
+func WithQueryLimitName(name string) Option {
+	return func(c *config) error {
+		return c.setQueryLimitName(name)
+	}
+}
+
+func WithQueryOffsetName(name string) Option {
+	return func(c *config) error {
+		return c.setQueryOffsetName(name)
+	}
+}
+
+func WithQueryAfterName(name string) Option {
+	return func(c *config) error {
+		return c.setQueryAfterName(name)
+	}
+}
+
+func WithQueryBeforeName(name string) Option {
+	return func(c *config) error {
+		return c.setQueryBeforeName(name)
+	}
+}
+
+func WithCollectErrors(collect bool) Option {
+	return func(c *config) error {
+		c.collectErrors = collect
+
+		return nil
+	}
+}
+
+func WithStrictSort(strict bool) Option {
+	return func(c *config) error {
+		c.strictSort = strict
+
+		return nil
+	}
+}
+
+func WithSortMax(n int) Option {
+	return func(c *config) error {
+		return c.setSortMax(n)
+	}
+}
+
+func WithDefaultSort(orders ...Order) Option {
+	return func(c *config) error {
+		return c.setDefaultSort(orders...)
+	}
+}
+
+func WithScope(sets ...FieldSet) Option {
+	return func(c *config) error {
+		c.setScope(sets...)
+
+		return nil
+	}
+}
+
+func WithScopeFunc(fn ScopeFunc) Option {
+	return func(c *config) error {
+		return c.setScopeFunc(fn)
+	}
+}
+
+func WithLimits(limits Limits) Option {
+	return func(c *config) error {
+		return c.setLimits(limits)
+	}
+}
+
+func WithTimeFormat(f TimeFormat) Option {
+	return func(c *config) error {
+		return c.setTimeFormat(f)
+	}
+}
+
+func (c *config) setFilterTag(filterTag string) error {
+	if filterTag == "" {
+		return fmt.Errorf("%w: filter tag cannot be empty", ErrInvalidOption)
+	}
+
+	return c.parseTags(filterTag, c.sortTag)
+}
+
+func (c *config) setSortTag(sortTag string) error {
+	if sortTag == "" {
+		return fmt.Errorf("%w: sort tag cannot be empty", ErrInvalidOption)
+	}
+
+	return c.parseTags(c.filterTag, sortTag)
+}
+
+// parseTags parses the tags of the struct, and applies the overrides of
+// SetOps and SetColumn again.
+func (c *config) parseTags(filterTag, sortTag string) error {
+	tags, err := ParseStruct(c.value, filterTag, sortTag)
+	if err != nil {
+		return err
+	}
+
+	for field, ops := range c.ops {
+		tag, ok := tags[field]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+
+		tag.Ops = ops
+	}
+
+	for field, column := range c.columns {
+		tag, ok := tags[field]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+
+		tag.Column = column
+	}
+
+	c.filterTag = filterTag
+	c.sortTag = sortTag
+	c.tags = tags
+	c.types = fieldTypes(c.value, tags)
+
+	return nil
+}
+
+func (c *config) setLimitRange(min, max int) error {
+	if min == 0 || max == 0 {
+		return fmt.Errorf("%w: limit and offset cannot be 0", ErrInvalidOption)
+	}
+
+	c.limitMin = min
+	c.limitMax = max
+
+	return nil
+}
+
+func (c *config) setParsers(parsers map[string]ParserFn) error {
+	if len(parsers) == 0 {
+		return fmt.Errorf("%w: no parsers specified", ErrInvalidOption)
+	}
+
+	// The map is copied, so that the later SetParser does not modify the
+	// caller's map, which may be shared by the other decoders.
+	c.parsers = make(map[string]ParserFn, len(parsers))
+	for name, fn := range parsers {
+		c.parsers[name] = fn
+	}
+
+	return nil
+}
+
+func (c *config) setParser(name string, parserFn ParserFn) error {
+	if parserFn == nil {
+		return fmt.Errorf("%w: parser %q cannot be nil", ErrInvalidOption, name)
+	}
+
+	c.parsers[name] = parserFn
+
+	return nil
+}
+
+func (c *config) setTypeParser(t reflect.Type, fn ParserFn) {
+	if c.typeParsers == nil {
+		c.typeParsers = make(map[reflect.Type]ParserFn)
+	}
+
+	c.typeParsers[t] = fn
+}
+
+func (c *config) setOps(field string, ops Op) error {
+	if field == "" {
+		return fmt.Errorf("%w: set ops field cannot be empty", ErrInvalidOption)
+	}
+
+	if _, ok := c.tags[field]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownField, field)
+	}
+
+	if !ops.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidOp, field)
+	}
+
+	// The tag is copied, so that the filters that are already decoded are not
+	// modified.
+	tag := c.tags[field].clone()
+	tag.Ops = ops
+	c.tags[field] = tag
+
+	if c.ops == nil {
+		c.ops = make(map[string]Op)
+	}
+
+	c.ops[field] = ops
+
+	return nil
+}
+
+func (c *config) setColumn(field, column string) error {
+	if field == "" {
+		return fmt.Errorf("%w: set column field cannot be empty", ErrInvalidOption)
+	}
+
+	if column == "" {
+		return fmt.Errorf("%w: set column name cannot be empty", ErrInvalidOption)
+	}
+
+	if _, ok := c.tags[field]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownField, field)
+	}
+
+	tag := c.tags[field].clone()
+	tag.Column = column
+	c.tags[field] = tag
+
+	if c.columns == nil {
+		c.columns = make(map[string]string)
+	}
+
+	c.columns[field] = column
+
+	return nil
+}
+
+func (c *config) setDialect(dialect Dialect) error {
+	if dialect == nil {
+		return fmt.Errorf("%w: dialect cannot be nil", ErrInvalidOption)
+	}
+
+	c.dialect = dialect
+
+	return nil
+}
+
+func (c *config) setReservedKeys(keys ...string) error {
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("%w: reserved key cannot be empty", ErrInvalidOption)
+		}
+	}
+
+	c.reserved = append(c.reserved, keys...)
+
+	return nil
+}
+
+func (c *config) setIgnoreKeys(keys ...string) error {
+	for _, key := range keys {
+		if key == "" || key == "*" {
+			return fmt.Errorf("%w: ignore key cannot be empty", ErrInvalidOption)
+		}
+	}
+
+	c.ignoreKeys = append(c.ignoreKeys, keys...)
+
+	return nil
+}
+
+func (c *config) setQuerySortName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: query sort name cannot be empty", ErrInvalidOption)
+	}
+
+	c.querySort = name
+
+	return nil
+}
+
+func (c *config) setQueryLimitName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: query limit name cannot be empty", ErrInvalidOption)
+	}
+
+	c.queryLimit = name
+
+	return nil
+}
+
+func (c *config) setQueryOffsetName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: query offset name cannot be empty", ErrInvalidOption)
+	}
+
+	c.queryOffset = name
+
+	return nil
+}
+
+func (c *config) setQueryAfterName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: query after name cannot be empty", ErrInvalidOption)
+	}
+
+	c.queryAfter = name
+
+	return nil
+}
+
+func (c *config) setQueryBeforeName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: query before name cannot be empty", ErrInvalidOption)
+	}
+
+	c.queryBefore = name
+
+	return nil
+}
+
+// setScope copies the field sets, so that the caller's slice is not shared.
+func (c *config) setScope(sets ...FieldSet) {
+	c.scope = append([]FieldSet(nil), sets...)
+}
+
+func (c *config) setSortMax(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: sort max cannot be negative", ErrInvalidOption)
+	}
+
+	c.sortMax = n
+
+	return nil
+}
+
+func (c *config) setDefaultSort(orders ...Order) error {
+	// The fields are checked and resolved to the columns when decoding, as
+	// the tags and the columns may be changed by the other options, see
+	// Validate.
+	sorts := make([]Order, len(orders))
+	for i, o := range orders {
+		if o.Direction == "" {
+			o.Direction = SortDirectionAscending
+		}
+
+		if !o.Direction.Valid() {
+			return fmt.Errorf("%w: %q", ErrInvalidSortDirection, o.Direction)
+		}
+
+		if o.Option == "" {
+			o.Option = o.Direction.DefaultOption()
+		}
+
+		if !o.Option.Valid() {
+			return fmt.Errorf("%w: %q", ErrInvalidSortOption, o.Option)
+		}
+
+		o.Column = ""
+		sorts[i] = o
+	}
+
+	c.defaultSort = sorts
+
+	return nil
+}
+
+func (c *config) setScopeFunc(fn ScopeFunc) error {
+	if fn == nil {
+		return fmt.Errorf("%w: scope func cannot be nil", ErrInvalidOption)
+	}
+
+	c.scopeFn = fn
+
+	return nil
+}
+
+func (c *config) setLimits(limits Limits) error {
+	if limits.Depth < 0 || limits.Predicates < 0 || limits.Values < 0 || limits.ValueLength < 0 || limits.Fields < 0 {
+		return fmt.Errorf("%w: limits cannot be negative", ErrInvalidOption)
+	}
+
+	c.limits = limits
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package goql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+)
+
+func TestNewDecoderE(t *testing.T) {
+	type User struct {
+		Name string `sort:"true"`
+		Age  int
+	}
+
+	tests := []struct {
+		name string
+		opts []goql.Option
+		err  error
+	}{
+		{"no options", nil, nil},
+		{"valid options", []goql.Option{
+			goql.WithOps("age", goql.OpEq),
+			goql.WithColumn("name", "u.name"),
+			goql.WithLimitRange(1, 100),
+			goql.WithDefaultSort(goql.Order{Field: "name"}),
+		}, nil},
+		{"empty filter tag", []goql.Option{goql.WithFilterTag("")}, goql.ErrInvalidOption},
+		{"zero limit", []goql.Option{goql.WithLimitRange(0, 10)}, goql.ErrInvalidOption},
+		{"nil dialect", []goql.Option{goql.WithDialect(nil)}, goql.ErrInvalidOption},
+		{"unknown field ops", []goql.Option{goql.WithOps("email", goql.OpEq)}, goql.ErrUnknownField},
+		{"invalid ops", []goql.Option{goql.WithOps("age", 0)}, goql.ErrInvalidOp},
+		{"unsortable default sort", []goql.Option{goql.WithDefaultSort(goql.Order{Field: "age"})}, goql.ErrUnsortableField},
+		{"nop parser", []goql.Option{goql.WithParser("int", goql.ParseNop)}, goql.ErrUnknownParser},
+		{"nil type parser", []goql.Option{goql.WithTypeParser[int](nil)}, goql.ErrInvalidOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec, err := goql.NewDecoderE[User](tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if _, err := dec.DecodeString("name.eq=john"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		type Account struct {
+			Email string `q:",type:email"`
+		}
+
+		_, err := goql.NewDecoderE[Account]()
+		if !errors.Is(err, goql.ErrUnknownParser) {
+			t.Fatalf("expected %v, got %v", goql.ErrUnknownParser, err)
+		}
+
+		_, err = goql.NewDecoderE[Account](goql.WithParser("email", goql.ParseString))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		type Account struct {
+			Name string `q:"name,ops:eq,col:n"`
+		}
+
+		_, err := goql.NewDecoderE[Account]()
+		if !errors.Is(err, goql.ErrInvalidOption) {
+			t.Fatalf("expected %v, got %v", goql.ErrInvalidOption, err)
+		}
+	})
+}
+
+func TestNewDecoderEOptionsOrder(t *testing.T) {
+	type User struct {
+		Age int `filter:"age" order:"true"`
+	}
+
+	scope := []goql.FieldSet{{Name: "deleted_at", Op: goql.OpIs, Values: []string{"null"}}}
+
+	dec, err := goql.NewDecoderE[User](
+		goql.WithDefaultSort(goql.Order{Field: "age"}),
+		goql.WithOps("age", goql.OpEq),
+		goql.WithColumn("age", "u.age"),
+		goql.WithScope(scope...),
+		goql.WithFilterTag("filter"),
+		goql.WithSortTag("order"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The caller's slice is copied.
+	scope[0].Name = "tenant_id"
+
+	if _, err := dec.DecodeString("age.gt=10"); !errors.Is(err, goql.ErrUnknownOperator) {
+		t.Fatalf("expected %v, got %v", goql.ErrUnknownOperator, err)
+	}
+
+	f, err := dec.DecodeString("age.eq=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, got := "deleted_at", f.And[0].Name; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	if exp, got := "u.age", f.And[1].Column; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+
+	if exp, got := "u.age", f.Sort[0].Column; exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+}
@@ -8,6 +8,9 @@ import (
 var (
 	ErrInvalidSortDirection = errors.New("goql: invalid sort direction")
 	ErrInvalidSortOption    = errors.New("goql: invalid sort option")
+	ErrUnsortableField      = errors.New("goql: unsortable field")
+	ErrDuplicateSortField   = errors.New("goql: duplicate sort field")
+	ErrTooManySortFields    = errors.New("goql: too many sort fields")
 )
 
 //https://www.postgresql.org/docs/current/queries-order.html#:~:text=The%20NULLS%20FIRST%20and%20NULLS,order%2C%20and%20NULLS%20LAST%20otherwise.
@@ -46,18 +49,37 @@ type Order struct {
 	Field     string
 	Direction SortDirection
 	Option    SortOption
+
+	// Column is the resolved SQL column of the field. It is set by the
+	// Decoder, and is never taken from the querystring.
+	Column string
 }
 
 func (o Order) String() string {
 	return fmt.Sprintf("%s %s %s", o.Field, o.Direction, o.Option)
 }
 
+// Query returns the query string value of the order, e.g.
+// `name.desc.nullsfirst`.
+func (o Order) Query() string {
+	return fmt.Sprintf("%s.%s.%s", o.Field, o.Direction, o.Option)
+}
+
 func NewOrder(s string) (*Order, error) {
 	if s == "" {
 		return nil, nil
 	}
 
-	field, direction, option := Split3(s, ".")
+	field, rest := Split2(s, ".")
+
+	return newOrder(field, rest)
+}
+
+// newOrder parses the order with the field already separated from the
+// direction and option, e.g. `desc.nullsfirst`. This allows the field to be
+// nested, e.g. `address.city`.
+func newOrder(field, s string) (*Order, error) {
+	direction, option := Split2(s, ".")
 	if direction == "" {
 		return &Order{
 			Field:     field,
@@ -14,13 +14,13 @@ func TestOrder(t *testing.T) {
 		exp   *goql.Order
 	}{
 		{"", nil},
-		{"name", &goql.Order{"name", "asc", "nullslast"}},
-		{"name.asc", &goql.Order{"name", "asc", "nullslast"}},
-		{"name.desc", &goql.Order{"name", "desc", "nullsfirst"}},
-		{"name.asc.nullsfirst", &goql.Order{"name", "asc", "nullsfirst"}},
-		{"name.asc.nullslast", &goql.Order{"name", "asc", "nullslast"}},
-		{"name.desc.nullsfirst", &goql.Order{"name", "desc", "nullsfirst"}},
-		{"name.desc.nullslast", &goql.Order{"name", "desc", "nullslast"}},
+		{"name", &goql.Order{"name", "asc", "nullslast", ""}},
+		{"name.asc", &goql.Order{"name", "asc", "nullslast", ""}},
+		{"name.desc", &goql.Order{"name", "desc", "nullsfirst", ""}},
+		{"name.asc.nullsfirst", &goql.Order{"name", "asc", "nullsfirst", ""}},
+		{"name.asc.nullslast", &goql.Order{"name", "asc", "nullslast", ""}},
+		{"name.desc.nullsfirst", &goql.Order{"name", "desc", "nullsfirst", ""}},
+		{"name.desc.nullslast", &goql.Order{"name", "desc", "nullslast", ""}},
 	}
 
 	for _, tt := range tests {
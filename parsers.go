@@ -3,16 +3,29 @@ package goql
 import (
 	"encoding/json"
 	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	bitSize16 = 16
-	bitSize32 = 32
-	bitSize64 = 64
+	bitSize8   = 8
+	bitSize16  = 16
+	bitSize32  = 32
+	bitSize64  = 64
+	bitSize128 = 128
 
 	base10 = 10
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	uuidRe    = regexp.MustCompile(`^(?i)(urn:uuid:)?\{?([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|[0-9a-f]{32})\}?$`)
+	decimalRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
 )
 
 // ParserFn handles conversion of the querystring `string` input to the
@@ -20,29 +33,40 @@ const (
 type ParserFn func(s string) (any, error)
 
 // NewParsers returns a list of default parsers. This can be extended, and set
-// back to the Decoder.
+// back to the Decoder. The parsers of the pointers and the arrays are derived
+// from the parsers of their types, e.g. `*int` and `[]int` uses the `int`
+// parser.
 func NewParsers() map[string]ParserFn {
 	return map[string]ParserFn{
 		"time.Time":       ParseTime,
-		"*time.Time":      ParseStringPointer[time.Time],
+		"time.Duration":   ParseDuration,
 		"bool":            ParseBool,
-		"*bool":           ParseStringPointer[bool],
 		"float32":         ParseFloat32,
-		"*float32":        ParseNumericPointer[float32],
 		"float64":         ParseFloat64,
-		"*float64":        ParseNumericPointer[float64],
+		"int8":            ParseInt8,
 		"int16":           ParseInt16,
-		"*int16":          ParseNumericPointer[int16],
 		"int32":           ParseInt32,
-		"*int32":          ParseNumericPointer[int32],
 		"int64":           ParseInt64,
-		"*int64":          ParseNumericPointer[int64],
 		"int":             ParseInt,
-		"*int":            ParseNumericPointer[int],
+		"uint8":           ParseUint8,
+		"uint16":          ParseUint16,
+		"uint32":          ParseUint32,
+		"uint64":          ParseUint64,
+		"uint":            ParseUint,
+		"uintptr":         ParseUintptr,
+		"complex64":       ParseComplex64,
+		"complex128":      ParseComplex128,
 		"string":          ParseString,
-		"*string":         ParseStringPointer[string],
 		"json.RawMessage": ParseJSON,
 		"[]byte":          ParseByte,
+		"netip.Addr":      ParseAddr,
+		"netip.Prefix":    ParsePrefix,
+		"date":            ParseDate,
+		"uuid":            ParseUUID,
+		"decimal":         ParseDecimal,
+		"numeric":         ParseDecimal,
+		"inet":            ParseInet,
+		"cidr":            ParseCIDR,
 		"":                ParseNop,
 	}
 }
@@ -61,19 +85,145 @@ func Map[T, K any](in []T, fn func(T) (K, error)) ([]K, error) {
 	return res, nil
 }
 
-// ParseTime parses string to time with the format RFC3339.
+// ParseTime parses string to time with the DefaultTimeLayouts in UTC, see
+// TimeFormat.
 func ParseTime(in string) (any, error) {
-	t, err := time.Parse(time.RFC3339, in)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	return TimeFormat{}.Parse(in)
+}
+
+// ParseNop is the placeholder parser for the types that are not implemented.
+// The decoder fails with ErrUnknownParser for fields with the nop parser.
+func ParseNop(in string) (any, error) {
+	return nil, fmt.Errorf("%w: parser not implemented", ErrUnknownParser)
+}
+
+// findParser returns the parser for the type, and the Go type of the field,
+// which may be nil. The parser is found in order:
+//  1. the parser registered for the Go type, see RegisterParser.
+//  2. the parser of the type name, e.g. `*int`, `[]int` or the `type:` tag.
+//  3. the parser of the pointer's type, e.g. `int` for `*int`.
+//  4. the parser of the array's element, e.g. `int` for `[]int`.
+//  5. the parser derived from the Go type, see typeParser.
+//  6. the parser of the kind, e.g. `string` for `type Status string`.
+//
+// The values of the map fields are parsed as JSON. The values are validated
+// after parsing, see ParseValidate.
+func findParser(c *config, t Type, rt reflect.Type) (ParserFn, bool) {
+	if t.Map {
+		fn, ok := lookupParser(c.parsers, jsonType)
+		if !ok {
+			return nil, false
+		}
+
+		return ParseValidate(fn), true
 	}
 
-	return t, nil
+	fn, ok := derivedParser(c, t.Name, t.Kind, rt)
+	if !ok {
+		return nil, false
+	}
+
+	return ParseValidate(fn), true
 }
 
-// ParseNop is a nop parser.
-func ParseNop(in string) (any, error) {
-	panic("goql: parser not implemented")
+func derivedParser(c *config, name string, kind reflect.Kind, rt reflect.Type) (ParserFn, bool) {
+	elem := strings.TrimPrefix(strings.TrimPrefix(name, "*"), "[]")
+	if fn, ok := c.typeParsers[rt]; ok && rt != nil && elem == name {
+		return fn, true
+	}
+
+	// The time.Time values are parsed with the TimeFormat of the decoder,
+	// unless a parser is registered, see SetTimeFormat.
+	if name == "time.Time" && c.formatsTime() {
+		return c.timeFormat.Parse, true
+	}
+
+	if fn, ok := lookupParser(c.parsers, name); ok {
+		return fn, true
+	}
+
+	if elem := strings.TrimPrefix(name, "*"); elem != name {
+		fn, ok := derivedParser(c, elem, kind, rt)
+		if !ok {
+			return nil, false
+		}
+
+		// The pointer to the array, e.g. `*[]int`, is parsed per element.
+		if strings.HasPrefix(elem, "[]") {
+			return fn, true
+		}
+
+		return ParsePointer(fn, rt), true
+	}
+
+	if elem := strings.TrimPrefix(name, "[]"); elem != name {
+		return derivedParser(c, elem, kind, rt)
+	}
+
+	if rt != nil {
+		return typeParser(c.parsers, rt)
+	}
+
+	return kindParser(c.parsers, kind)
+}
+
+func kindParser(parsers map[string]ParserFn, kind reflect.Kind) (ParserFn, bool) {
+	switch kind {
+	case reflect.Invalid, reflect.Interface, reflect.Struct, reflect.Map, reflect.Pointer, reflect.Slice, reflect.Array:
+		return nil, false
+	default:
+		return lookupParser(parsers, kind.String())
+	}
+}
+
+func lookupParser(parsers map[string]ParserFn, name string) (ParserFn, bool) {
+	fn, ok := parsers[name]
+	if !ok || isNop(fn) {
+		return nil, false
+	}
+
+	return fn, true
+}
+
+// ParsePointer returns the parser that parses to the pointer of the type t,
+// or the nil pointer if the input is `null`. The value is converted to t, e.g.
+// `int64` to `int16`. If t is nil, the pointer has the type of the value, and
+// `null` is parsed as nil.
+func ParsePointer(fn ParserFn, t reflect.Type) ParserFn {
+	return func(in string) (any, error) {
+		if in == "null" {
+			if t == nil {
+				return nil, nil
+			}
+
+			return reflect.Zero(reflect.PointerTo(t)).Interface(), nil
+		}
+
+		v, err := fn(in)
+		if err != nil || v == nil {
+			return v, err
+		}
+
+		rv := reflect.ValueOf(v)
+		if t != nil && rv.Type().ConvertibleTo(t) {
+			rv = rv.Convert(t)
+		}
+
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+
+		return ptr.Interface(), nil
+	}
+}
+
+// isNop returns true if the parser is ParseNop.
+func isNop(fn ParserFn) bool {
+	return sameParser(fn, ParseNop)
+}
+
+// sameParser returns true if the parsers are the same function.
+func sameParser(a, b ParserFn) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
 }
 
 // ParseBool parses string to bool.
@@ -88,12 +238,22 @@ func ParseBool(in string) (any, error) {
 
 // ParseFloat32 parses string to float32.
 func ParseFloat32(in string) (any, error) {
-	return parseFloat(in, bitSize32)
+	n, err := parseFloat(in, bitSize32)
+	if err != nil {
+		return nil, err
+	}
+
+	return float32(n), nil
 }
 
 // ParseFloat64 parses string to float64.
 func ParseFloat64(in string) (any, error) {
-	return parseFloat(in, bitSize64)
+	n, err := parseFloat(in, bitSize64)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
 }
 
 // ParseInt parses string to int.
@@ -106,19 +266,221 @@ func ParseInt(in string) (any, error) {
 	return n, nil
 }
 
+// ParseInt8 parses string to int8.
+func ParseInt8(in string) (any, error) {
+	n, err := parseInt(in, bitSize8)
+	if err != nil {
+		return nil, err
+	}
+
+	return int8(n), nil
+}
+
 // ParseInt16 parses string to int16.
 func ParseInt16(in string) (any, error) {
-	return parseInt(in, bitSize16)
+	n, err := parseInt(in, bitSize16)
+	if err != nil {
+		return nil, err
+	}
+
+	return int16(n), nil
 }
 
 // ParseInt32 parses string to int32.
 func ParseInt32(in string) (any, error) {
-	return parseInt(in, bitSize32)
+	n, err := parseInt(in, bitSize32)
+	if err != nil {
+		return nil, err
+	}
+
+	return int32(n), nil
 }
 
 // ParseInt64 parses string to int64.
 func ParseInt64(in string) (any, error) {
-	return parseInt(in, bitSize64)
+	n, err := parseInt(in, bitSize64)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// ParseUint parses string to uint.
+func ParseUint(in string) (any, error) {
+	n, err := strconv.ParseUint(in, base10, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return uint(n), nil
+}
+
+// ParseUint8 parses string to uint8.
+func ParseUint8(in string) (any, error) {
+	n, err := parseUint(in, bitSize8)
+	if err != nil {
+		return nil, err
+	}
+
+	return uint8(n), nil
+}
+
+// ParseUint16 parses string to uint16.
+func ParseUint16(in string) (any, error) {
+	n, err := parseUint(in, bitSize16)
+	if err != nil {
+		return nil, err
+	}
+
+	return uint16(n), nil
+}
+
+// ParseUint32 parses string to uint32.
+func ParseUint32(in string) (any, error) {
+	n, err := parseUint(in, bitSize32)
+	if err != nil {
+		return nil, err
+	}
+
+	return uint32(n), nil
+}
+
+// ParseUint64 parses string to uint64.
+func ParseUint64(in string) (any, error) {
+	n, err := parseUint(in, bitSize64)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// ParseUintptr parses string to uintptr.
+func ParseUintptr(in string) (any, error) {
+	n, err := strconv.ParseUint(in, base10, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return uintptr(n), nil
+}
+
+// ParseComplex64 parses string to complex64, e.g. `1+2i`.
+func ParseComplex64(in string) (any, error) {
+	c, err := strconv.ParseComplex(in, bitSize64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return complex64(c), nil
+}
+
+// ParseComplex128 parses string to complex128, e.g. `1+2i`.
+func ParseComplex128(in string) (any, error) {
+	c, err := strconv.ParseComplex(in, bitSize128)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return c, nil
+}
+
+// ParseDuration parses string to time.Duration, e.g. `1h30m`.
+func ParseDuration(in string) (any, error) {
+	d, err := time.ParseDuration(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return d, nil
+}
+
+// ParseDate parses string to time with the format `2006-01-02`, for the
+// `type:date` tag.
+func ParseDate(in string) (any, error) {
+	t, err := time.Parse(dateLayout, in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return t, nil
+}
+
+// ParseUUID validates the UUID, and returns it in the canonical lowercase
+// format, for the `type:uuid` tag.
+func ParseUUID(in string) (any, error) {
+	if !uuidRe.MatchString(in) {
+		return nil, fmt.Errorf("%w: invalid uuid %q", ErrBadValue, in)
+	}
+
+	s := strings.TrimPrefix(strings.ToLower(in), "urn:uuid:")
+	s = strings.Trim(s, "{}")
+	if len(s) == 32 {
+		s = s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+	}
+
+	return s, nil
+}
+
+// ParseDecimal validates the decimal, and returns it as string, so that the
+// precision is not lost, for the `type:decimal` or `type:numeric` tags.
+func ParseDecimal(in string) (any, error) {
+	if !decimalRe.MatchString(in) {
+		return nil, fmt.Errorf("%w: invalid decimal %q", ErrBadValue, in)
+	}
+
+	return in, nil
+}
+
+// ParseAddr parses string to netip.Addr, e.g. `10.0.0.1`.
+func ParseAddr(in string) (any, error) {
+	addr, err := netip.ParseAddr(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return addr, nil
+}
+
+// ParsePrefix parses string to netip.Prefix, e.g. `10.0.0.0/8`.
+func ParsePrefix(in string) (any, error) {
+	prefix, err := netip.ParsePrefix(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return prefix, nil
+}
+
+// ParseInet validates the IP address or the network, e.g. `10.0.0.1` or
+// `10.0.0.0/8`, and returns it as string, for the `type:inet` tag.
+func ParseInet(in string) (any, error) {
+	if _, err := netip.ParseAddr(in); err == nil {
+		return in, nil
+	}
+
+	if _, err := netip.ParsePrefix(in); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return in, nil
+}
+
+// ParseCIDR validates the network, e.g. `10.0.0.0/8`, and returns it as
+// string, for the `type:cidr` tag. The bits to the right of the mask must be
+// zero, the same as Postgres.
+func ParseCIDR(in string) (any, error) {
+	prefix, err := netip.ParsePrefix(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	if prefix.Masked() != prefix {
+		return nil, fmt.Errorf("%w: invalid cidr %q", ErrBadValue, in)
+	}
+
+	return in, nil
 }
 
 // ParseString returns the string as it is.
@@ -171,20 +533,29 @@ func ParseNumericPointer[T int | int16 | int32 | int64 | float32 | float64](in s
 	return t, nil
 }
 
-func parseFloat(in string, bitSize int) (any, error) {
+func parseFloat(in string, bitSize int) (float64, error) {
 	f, err := strconv.ParseFloat(in, bitSize)
 	if err != nil {
-		return nil, fmt.Errorf("%w, %s", ErrBadValue, err)
+		return 0, fmt.Errorf("%w, %s", ErrBadValue, err)
 	}
 
 	return f, nil
 }
 
-func parseInt(in string, bitSize int) (any, error) {
+func parseInt(in string, bitSize int) (int64, error) {
 	f, err := strconv.ParseInt(in, base10, bitSize)
 	if err != nil {
-		return nil, fmt.Errorf("%w, %s", ErrBadValue, err)
+		return 0, fmt.Errorf("%w, %s", ErrBadValue, err)
 	}
 
 	return f, nil
 }
+
+func parseUint(in string, bitSize int) (uint64, error) {
+	n, err := strconv.ParseUint(in, base10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	return n, nil
+}
@@ -2,8 +2,13 @@ package goql_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alextanhongpin/goql"
 	"github.com/google/go-cmp/cmp"
@@ -33,6 +38,27 @@ func TestParser(t *testing.T) {
 		{"true", "bool", true},
 		{"null", "*bool", (*bool)(nil)},
 		{string(b), "json.RawMessage", json.RawMessage(b)},
+		{"-100", "int8", int8(-100)},
+		{"-100", "int16", int16(-100)},
+		{"-100", "int32", int32(-100)},
+		{"100", "uint", uint(100)},
+		{"100", "uint8", uint8(100)},
+		{"100", "uint16", uint16(100)},
+		{"100", "uint32", uint32(100)},
+		{"1.5", "float32", float32(1.5)},
+		{"18446744073709551615", "uint64", uint64(18446744073709551615)},
+		{"100", "uintptr", uintptr(100)},
+		{"1+2i", "complex64", complex64(1 + 2i)},
+		{"-1.5-2i", "complex128", complex128(-1.5 - 2i)},
+		{"1h30m", "time.Duration", 90 * time.Minute},
+		{"2024-02-29", "date", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"6BA7B810-9DAD-11D1-80B4-00C04FD430C8", "uuid", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"6ba7b8109dad11d180b400c04fd430c8", "uuid", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"-12.50", "decimal", "-12.50"},
+		{"10.0.0.1", "netip.Addr", netip.MustParseAddr("10.0.0.1")},
+		{"10.0.0.0/8", "netip.Prefix", netip.MustParsePrefix("10.0.0.0/8")},
+		{"10.0.0.1", "inet", "10.0.0.1"},
+		{"10.0.0.0/8", "cidr", "10.0.0.0/8"},
 	}
 
 	parsers := goql.NewParsers()
@@ -41,6 +67,14 @@ func TestParser(t *testing.T) {
 		name := fmt.Sprintf("parsing %s: %s", tt.typ, tt.inp)
 		t.Run(name, func(t *testing.T) {
 			parser, ok := parsers[tt.typ]
+
+			// The parsers of the pointers are derived from the parsers of
+			// their types.
+			if elem := strings.TrimPrefix(tt.typ, "*"); elem != tt.typ {
+				parser, ok = parsers[elem]
+				parser = goql.ParsePointer(parser, reflect.TypeOf(tt.exp).Elem())
+			}
+
 			if !ok {
 				t.FailNow()
 			}
@@ -49,10 +83,63 @@ func TestParser(t *testing.T) {
 			if err != nil {
 				t.Fatalf("parser error: %s", err)
 			}
-			if diff := cmp.Diff(tt.exp, res); diff != "" {
+			if diff := cmp.Diff(tt.exp, res, cmp.Comparer(func(a, b netip.Addr) bool { return a == b }), cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
 				t.Fatalf("expected+, got-: %s", diff)
 			}
 		})
 	}
 
+	for _, tt := range []struct{ inp, typ string }{
+		{"256", "uint8"},
+		{"-1", "uint"},
+		{"2024-02-30", "date"},
+		{"6ba7b810-9dad11d1-80b4-00c04fd430c8", "uuid"},
+		{"1/3", "decimal"},
+		{"10.0.0.1/8", "cidr"},
+		{"10.0.0", "inet"},
+	} {
+		t.Run(fmt.Sprintf("invalid %s: %s", tt.typ, tt.inp), func(t *testing.T) {
+			_, err := parsers[tt.typ](tt.inp)
+			if !errors.Is(err, goql.ErrBadValue) {
+				t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+			}
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	i := 100
+	b := true
+	n := int16(100)
+	f := float32(1.5)
+
+	tests := []struct {
+		name   string
+		inp    string
+		parser goql.ParserFn
+		typ    reflect.Type
+		exp    any
+	}{
+		{"null int", "null", goql.ParseInt, reflect.TypeOf(0), (*int)(nil)},
+		{"int", "100", goql.ParseInt, reflect.TypeOf(0), &i},
+		{"null bool", "null", goql.ParseBool, reflect.TypeOf(false), (*bool)(nil)},
+		{"bool", "true", goql.ParseBool, reflect.TypeOf(false), &b},
+		{"converted int16", "100", goql.ParseInt, reflect.TypeOf(int16(0)), &n},
+		{"converted float32", "1.5", goql.ParseFloat64, reflect.TypeOf(float32(0)), &f},
+		{"null without type", "null", goql.ParseInt, nil, nil},
+		{"without type", "100", goql.ParseInt, nil, &i},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := goql.ParsePointer(tt.parser, tt.typ)(tt.inp)
+			if err != nil {
+				t.Fatalf("parser error: %s", err)
+			}
+
+			if diff := cmp.Diff(tt.exp, res); diff != "" {
+				t.Fatalf("expected+, got-: %s", diff)
+			}
+		})
+	}
 }
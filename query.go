@@ -12,21 +12,83 @@ type Query struct {
 	Field  string
 	Op     Op
 	Values []string
+
+	// Negated is true when the operator is prefixed with `not`, e.g.
+	// `age.not.gt`.
+	Negated bool
+
+	// Quantifier is the quantifier of the array elements, e.g. `scores.anygt`
+	// has the quantifier OpAny and the op OpGt.
+	Quantifier Op
+
+	// Len is true when the length of the array is compared, e.g.
+	// `tags.len.gt`.
+	Len bool
 }
 
 func NewQuery(query string, values []string) *Query {
-	field, operator := Split2(query, ".")
-	op, _ := ParseOp(operator)
+	// The field may be nested, e.g. `address.city.eq`, so the operator is
+	// always the last part.
+	field, operator := query, ""
+	if i := strings.LastIndex(query, "."); i != -1 {
+		field, operator = query[:i], query[i+1:]
+	}
+
+	op, ok := ParseOp(operator)
+	var quantifier Op
+	if !ok {
+		quantifier, op = parseQuantifier(operator)
+	}
+
+	negated := strings.HasSuffix(field, "."+OpNot.String())
+	if negated {
+		field = strings.TrimSuffix(field, "."+OpNot.String())
+	}
+
+	length := strings.HasSuffix(field, "."+OpLen.String())
+	if length {
+		field = strings.TrimSuffix(field, "."+OpLen.String())
+	}
 
 	return &Query{
-		Field:  field,
-		Op:     op,
-		Values: values,
+		Field:      field,
+		Op:         op,
+		Values:     values,
+		Negated:    negated,
+		Quantifier: quantifier,
+		Len:        length,
 	}
 }
 
+// parseQuantifier parses the quantified operator, e.g. `anygt`.
+func parseQuantifier(operator string) (Op, Op) {
+	for _, q := range []Op{OpAny, OpAll} {
+		if !strings.HasPrefix(operator, q.String()) {
+			continue
+		}
+
+		op, ok := ParseOp(strings.TrimPrefix(operator, q.String()))
+		if ok && OpsQuantifiable.Has(op) {
+			return q, op
+		}
+	}
+
+	return 0, 0
+}
+
 func (q Query) String() string {
-	return fmt.Sprintf("%s.%s:%v", q.Field, q.Op, q.Values)
+	parts := []string{q.Field}
+	if q.Len {
+		parts = append(parts, OpLen.String())
+	}
+
+	if q.Negated {
+		parts = append(parts, OpNot.String())
+	}
+
+	parts = append(parts, q.Quantifier.String()+q.Op.String())
+
+	return fmt.Sprintf("%s:%v", strings.Join(parts, "."), q.Values)
 }
 
 func (q *Query) Validate() error {
@@ -34,7 +96,9 @@ func (q *Query) Validate() error {
 		return fmt.Errorf("%w: %s", ErrUnknownField, q)
 	}
 
-	if !q.Op.Valid() {
+	// `not`, `len` and the quantifiers are only valid as a prefix to the
+	// operator, e.g. `age.not.eq`, `tags.len.gt` or `scores.anygt`.
+	if !q.Op.Valid() || q.Op.Is(OpNot) || q.Op.Is(OpLen) || OpsQuantifier.Has(q.Op) {
 		return fmt.Errorf("%w: %s", ErrUnknownOperator, q)
 	}
 
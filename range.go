@@ -0,0 +1,118 @@
+package goql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range is the value of the `between` and `notbetween` ops. The bounds are
+// parsed by the field's parser. A nil bound is unbounded.
+type Range struct {
+	Lower    any
+	Upper    any
+	LowerInc bool
+	UpperInc bool
+}
+
+// String returns the range literal, e.g. `[10,20)`.
+func (r Range) String() string {
+	var lower, upper string
+	if r.Lower != nil {
+		lower = FormatValue(r.Lower)
+	}
+
+	if r.Upper != nil {
+		upper = FormatValue(r.Upper)
+	}
+
+	l, u := "(", ")"
+	if r.LowerInc {
+		l = "["
+	}
+
+	if r.UpperInc {
+		u = "]"
+	}
+
+	return fmt.Sprintf("%s%s,%s%s", l, lower, upper, u)
+}
+
+// ParseRange parses the values of the `between` and `notbetween` ops. The
+// values can be two repeated values, e.g. `age.between=10&age.between=20`, a
+// comma separated value, e.g. `age.between=10,20`, or a Postgres range
+// literal, e.g. `age.between=[10,20)`. The bounds are inclusive, unless
+// specified by the range literal. The bounds of range literals may be empty,
+// e.g. `[10,)`, which is unbounded.
+func ParseRange(values []string, parser ParserFn) (*Range, error) {
+	r := &Range{
+		LowerInc: true,
+		UpperInc: true,
+	}
+
+	var bounds []string
+	literal := false
+
+	switch len(values) {
+	case 1:
+		v, _ := Unquote(values[0], '"', '"')
+		if inner, lower, upper, ok := unquoteRange(v); ok {
+			literal = true
+			r.LowerInc = lower
+			r.UpperInc = upper
+			v = inner
+		}
+
+		bounds = SplitOutsideBrackets(v)
+
+		// The trailing empty bound is omitted, e.g. `10,`.
+		if strings.HasSuffix(v, ",") {
+			bounds = append(bounds, "")
+		}
+	case 2:
+		bounds = values
+	}
+
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("%w: range requires two values: %v", ErrBadValue, values)
+	}
+
+	if bounds[0] == "" && bounds[1] == "" || !literal && (bounds[0] == "" || bounds[1] == "") {
+		return nil, fmt.Errorf("%w: range requires bounds: %v", ErrBadValue, values)
+	}
+
+	var err error
+	if bounds[0] == "" {
+		r.LowerInc = false
+	} else if r.Lower, err = parseBound(bounds[0], parser); err != nil {
+		return nil, err
+	}
+
+	if bounds[1] == "" {
+		r.UpperInc = false
+	} else if r.Upper, err = parseBound(bounds[1], parser); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func parseBound(bound string, parser ParserFn) (any, error) {
+	bound, _ = Unquote(bound, '"', '"')
+
+	return parser(bound)
+}
+
+// unquoteRange removes the brackets of the range literal, and returns whether
+// the lower and upper bounds are inclusive.
+func unquoteRange(v string) (inner string, lower, upper, ok bool) {
+	if len(v) < 2 {
+		return v, false, false, false
+	}
+
+	l, u := v[0], v[len(v)-1]
+	if (l != '[' && l != '(') || (u != ']' && u != ')') {
+		return v, false, false, false
+	}
+
+	return v[1 : len(v)-1], l == '[', u == ']', true
+}
@@ -0,0 +1,91 @@
+package goql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRange(t *testing.T) {
+	parser := goql.NewParsers()["int"]
+
+	tests := []struct {
+		name   string
+		values []string
+		exp    *goql.Range
+		err    error
+	}{
+		{
+			name:   "comma separated",
+			values: []string{"10,20"},
+			exp:    &goql.Range{Lower: 10, Upper: 20, LowerInc: true, UpperInc: true},
+		},
+		{
+			name:   "repeated values",
+			values: []string{"10", "20"},
+			exp:    &goql.Range{Lower: 10, Upper: 20, LowerInc: true, UpperInc: true},
+		},
+		{
+			name:   "range literal",
+			values: []string{"[10,20)"},
+			exp:    &goql.Range{Lower: 10, Upper: 20, LowerInc: true},
+		},
+		{
+			name:   "quoted range literal",
+			values: []string{`"(10,20]"`},
+			exp:    &goql.Range{Lower: 10, Upper: 20, UpperInc: true},
+		},
+		{
+			name:   "unbounded lower",
+			values: []string{"(,20]"},
+			exp:    &goql.Range{Upper: 20, UpperInc: true},
+		},
+		{
+			name:   "unbounded upper",
+			values: []string{"[10,)"},
+			exp:    &goql.Range{Lower: 10, LowerInc: true},
+		},
+		{
+			name:   "missing bound",
+			values: []string{"10,"},
+			err:    goql.ErrBadValue,
+		},
+		{
+			name:   "unbounded",
+			values: []string{"(,)"},
+			err:    goql.ErrBadValue,
+		},
+		{
+			name:   "too many values",
+			values: []string{"1,2,3"},
+			err:    goql.ErrBadValue,
+		},
+		{
+			name:   "bad value",
+			values: []string{"[1,two]"},
+			err:    goql.ErrBadValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := goql.ParseRange(tt.values, parser)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if diff := cmp.Diff(tt.exp, r); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRangeString(t *testing.T) {
+	r := goql.Range{Lower: 10, LowerInc: true}
+	if exp, got := "[10,)", r.String(); exp != got {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+}
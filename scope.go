@@ -0,0 +1,97 @@
+package goql
+
+import (
+	"context"
+)
+
+// ScopeFunc adds the scope to the filter, e.g. the tenant of the request. The
+// filter is empty, and the field sets added to it are merged into every
+// decoded filter, the same way as the decoded `And` and `Or`.
+type ScopeFunc func(ctx context.Context, f *Filter) error
+
+// SetScope sets the field sets that are merged into every decoded filter,
+// e.g. `deleted_at is null`. The field name does not need to be a filterable
+// field, and the column defaults to the name.
+func (d *Decoder[T]) SetScope(sets ...FieldSet) *Decoder[T] {
+	d.setScope(sets...)
+
+	return d
+}
+
+// SetScopeFunc sets the callback that adds the scope of the request. The
+// scope is merged after the field sets of SetScope.
+func (d *Decoder[T]) SetScopeFunc(fn ScopeFunc) *Decoder[T] {
+	if err := d.setScopeFunc(fn); err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// Scope returns the field sets that are merged into the filters decoded with
+// the context.
+func (d *Decoder[T]) Scope(ctx context.Context) ([]FieldSet, error) {
+	sets := make([]FieldSet, 0, len(d.scope))
+	sets = append(sets, d.scope...)
+
+	if d.scopeFn != nil {
+		var f Filter
+		if err := d.scopeFn(ctx, &f); err != nil {
+			return nil, err
+		}
+
+		sets = append(sets, mergeAnd(&f)...)
+	}
+
+	for i := range sets {
+		sets[i] = d.resolve(sets[i])
+	}
+
+	return sets, nil
+}
+
+// applyScope merges the scope into the filter. The filter is wrapped as
+// `scope AND (a AND b OR c)`, so that the user cannot `or` around the scope.
+func (d *Decoder[T]) applyScope(ctx context.Context, f *Filter) error {
+	scope, err := d.Scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(scope) == 0 {
+		return nil
+	}
+
+	f.And = mergeAnd(f, scope...)
+	f.Scope = scope
+
+	return nil
+}
+
+// resolve sets the tag and column of the field set, if the field exists.
+func (d *Decoder[T]) resolve(fs FieldSet) FieldSet {
+	if tag, ok := d.tags[fs.Name]; ok && fs.Tag == nil {
+		fs.Tag = tag
+		if fs.Column == "" {
+			fs.Column = tag.Column
+		}
+	}
+
+	fs.And = d.resolveAll(fs.And)
+	fs.Or = d.resolveAll(fs.Or)
+
+	return fs
+}
+
+func (d *Decoder[T]) resolveAll(sets []FieldSet) []FieldSet {
+	if sets == nil {
+		return nil
+	}
+
+	res := make([]FieldSet, len(sets))
+	for i, fs := range sets {
+		res[i] = d.resolve(fs)
+	}
+
+	return res
+}
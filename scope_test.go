@@ -0,0 +1,105 @@
+package goql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+type tenantKey struct{}
+
+func TestScope(t *testing.T) {
+	type Post struct {
+		ID       int `sort:"true"`
+		Title    string
+		TenantID int `q:"tenant_id,col:p.tenant_id"`
+	}
+
+	dec := goql.NewDecoder[Post]().
+		SetScope(goql.FieldSet{Name: "deleted_at", Op: goql.OpIs, Value: nil, Values: []string{"null"}}).
+		SetScopeFunc(func(ctx context.Context, f *goql.Filter) error {
+			tenantID, ok := ctx.Value(tenantKey{}).(int)
+			if !ok {
+				return errors.New("missing tenant")
+			}
+
+			f.And = append(f.And, goql.FieldSet{Name: "tenant_id", Op: goql.OpEq, Value: tenantID})
+
+			return nil
+		})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, 42)
+
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "empty",
+			query: "",
+			sql:   "WHERE deleted_at is null AND p.tenant_id = $1",
+			args:  []any{42},
+		},
+		{
+			name:  "and",
+			query: "title.eq=go&tenant_id.eq=1",
+			sql:   "WHERE deleted_at is null AND p.tenant_id = $1 AND p.tenant_id = $2 AND title = $3",
+			args:  []any{42, 1, "go"},
+		},
+		{
+			name:  "or is wrapped",
+			query: "title.eq=go&or=tenant_id.eq:1&or=id.gt:0",
+			sql:   "WHERE deleted_at is null AND p.tenant_id = $1 AND (title = $2 OR id > $3 OR p.tenant_id = $4)",
+			args:  []any{42, "go", 0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeStringContext(ctx, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := 2, len(f.Scope); exp != got {
+				t.Fatalf("expected %v, got %v", exp, got)
+			}
+
+			sql, args, err := goql.BuildSQL(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff(tt.args, args); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+
+	t.Run("introspection", func(t *testing.T) {
+		scope, err := dec.Scope(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exp, got := "p.tenant_id", scope[1].Column; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+
+	t.Run("scope error", func(t *testing.T) {
+		_, err := dec.DecodeString("title.eq=go")
+		if exp, got := "missing tenant", err.Error(); exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package goql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SQLBuilder renders a decoded Filter to a parameterized SQL fragment. The
+// default dialect is Postgres.
+type SQLBuilder struct {
+	dialect Dialect
+	args    []any
+}
+
+// NewSQLBuilder returns a new SQLBuilder.
+func NewSQLBuilder() *SQLBuilder {
+	return &SQLBuilder{
+		dialect: Postgres{},
+	}
+}
+
+// BuildSQL is a shorthand for NewSQLBuilder().Build(f).
+func BuildSQL(f *Filter) (string, []any, error) {
+	return NewSQLBuilder().Build(f)
+}
+
+func (b *SQLBuilder) SetDialect(dialect Dialect) *SQLBuilder {
+	if dialect == nil {
+		panic("goql: dialect cannot be nil")
+	}
+
+	b.dialect = dialect
+
+	return b
+}
+
+// Build renders the filter as a `WHERE ... ORDER BY ... LIMIT ... OFFSET ...`
+// fragment, together with the arguments for the placeholders. Clauses that are
+// not set are omitted.
+//
+// The top-level `And` and `Or` are joined the same way as the querystring, so
+// `and=a&and=b&or=c` becomes `WHERE a AND b OR c`.
+func (b *SQLBuilder) Build(f *Filter) (string, []any, error) {
+	b.args = nil
+
+	clauses := make([]string, 0, 4)
+
+	where, err := b.where(f)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if where != "" {
+		clauses = append(clauses, "WHERE "+where)
+	}
+
+	if len(f.Sort) > 0 {
+		clauses = append(clauses, "ORDER BY "+b.orderBy(f.Sort))
+	}
+
+	if f.Limit != nil {
+		clauses = append(clauses, "LIMIT "+b.bind(*f.Limit))
+	}
+
+	if f.Offset != nil {
+		clauses = append(clauses, "OFFSET "+b.bind(*f.Offset))
+	}
+
+	return strings.Join(clauses, " "), b.args, nil
+}
+
+func (b *SQLBuilder) where(f *Filter) (string, error) {
+	ands, err := b.predicates(f.And)
+	if err != nil {
+		return "", err
+	}
+
+	ors, err := b.predicates(f.Or)
+	if err != nil {
+		return "", err
+	}
+
+	res := strings.Join(ands, " AND ")
+	if len(ors) == 0 {
+		return res, nil
+	}
+
+	if res == "" {
+		return strings.Join(ors, " OR "), nil
+	}
+
+	return res + " OR " + strings.Join(ors, " OR "), nil
+}
+
+func (b *SQLBuilder) orderBy(orders []Order) string {
+	res := make([]string, len(orders))
+	for i, o := range orders {
+		col := o.Column
+		if col == "" {
+			col = o.Field
+		}
+
+		res[i] = b.dialect.Order(b.dialect.Quote(col), o)
+	}
+
+	return strings.Join(res, ", ")
+}
+
+func (b *SQLBuilder) predicates(sets []FieldSet) ([]string, error) {
+	res := make([]string, len(sets))
+	for i, fs := range sets {
+		s, err := b.predicate(fs)
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = s
+	}
+
+	return res, nil
+}
+
+func (b *SQLBuilder) predicate(fs FieldSet) (string, error) {
+	var (
+		s   string
+		err error
+	)
+
+	switch fs.Op {
+	case OpAnd:
+		s, err = b.group(fs.And, " AND ")
+	case OpOr:
+		s, err = b.group(fs.Or, " OR ")
+	default:
+		col := fs.Column
+		if col == "" {
+			col = fs.Name
+		}
+
+		col = b.dialect.Quote(col)
+		if len(fs.Path) > 0 {
+			typ := Type{Name: "string"}
+			if fs.Tag != nil {
+				typ = fs.Tag.PathType(fs.Path)
+			}
+
+			pd, ok := b.dialect.(PathDialect)
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ErrUnsupportedOp, fs)
+			}
+
+			col = pd.Path(col, fs.Path, typ)
+		}
+
+		s, err = b.dialect.Predicate(col, fs, b.bind)
+	}
+
+	if err != nil || !fs.Negated {
+		return s, err
+	}
+
+	// Groups are already parenthesized, except for the empty groups.
+	if isGroup := fs.Op.Is(OpAnd) || fs.Op.Is(OpOr); !isGroup || !strings.HasPrefix(s, "(") {
+		s = "(" + s + ")"
+	}
+
+	return "NOT " + s, nil
+}
+
+func (b *SQLBuilder) group(sets []FieldSet, sep string) (string, error) {
+	// Empty conjunctions are true, and empty disjunctions are false.
+	if len(sets) == 0 && sep == " AND " {
+		return "1 = 1", nil
+	}
+
+	if len(sets) == 0 {
+		return "1 = 0", nil
+	}
+
+	res, err := b.predicates(sets)
+	if err != nil {
+		return "", err
+	}
+
+	return "(" + strings.Join(res, sep) + ")", nil
+}
+
+func (b *SQLBuilder) bind(value any) string {
+	b.args = append(b.args, value)
+
+	return b.dialect.Placeholder(len(b.args))
+}
+
+var sqlOps = map[Op]string{
+	OpEq:         "=",
+	OpNeq:        "<>",
+	OpLt:         "<",
+	OpLte:        "<=",
+	OpGt:         ">",
+	OpGte:        ">=",
+	OpLike:       "like",
+	OpIlike:      "ilike",
+	OpNotLike:    "not like",
+	OpNotIlike:   "not ilike",
+	OpIn:         "in",
+	OpNotIn:      "not in",
+	OpIs:         "is",
+	OpIsNot:      "is not",
+	OpFts:        "to_tsquery",
+	OpPlFts:      "plainto_tsquery",
+	OpPhFts:      "phraseto_tsquery",
+	OpWFts:       "websearch_to_tsquery",
+	OpCs:         "@>",
+	OpCd:         "<@",
+	OpOv:         "&&",
+	OpSl:         "<<",
+	OpSr:         ">>",
+	OpNxr:        "&<",
+	OpNxl:        "&>",
+	OpAdj:        "-|-",
+	OpBetween:    "between",
+	OpNotBetween: "not between",
+	OpHasKey:     "?",
+	OpHasAnyKeys: "?|",
+	OpHasAllKeys: "?&",
+}
@@ -0,0 +1,350 @@
+package goql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildSQL(t *testing.T) {
+	type Book struct {
+		ID          int
+		Author      string
+		Title       string
+		PublishYear *int     `q:"publish_year" sort:"true"`
+		Tags        []string `q:",type:[]string"`
+		Authors     []string `q:",type:[]string,ops:eq,len"`
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "empty",
+			query: "",
+			sql:   "",
+		},
+		{
+			name:  "comparable",
+			query: "author.eq=Robert Greene&id.neq=1&id.lt=2&id.lte=3&id.gt=4&id.gte=5",
+			sql:   "WHERE author = $1 AND id > $2 AND id >= $3 AND id < $4 AND id <= $5 AND id <> $6",
+			args:  []any{"Robert Greene", 4, 5, 2, 3, 1},
+		},
+		{
+			name:  "like single",
+			query: "title.like=law%25",
+			sql:   "WHERE title like $1",
+			args:  []any{"law%"},
+		},
+		{
+			name:  "ilike many",
+			query: "title.ilike=law%25&title.ilike=master%25",
+			sql:   "WHERE title ilike any(array[$1, $2])",
+			args:  []any{"law%", "master%"},
+		},
+		{
+			name:  "not ilike many",
+			query: "title.notilike=law%25&title.notilike=master%25",
+			sql:   "WHERE title not ilike all(array[$1, $2])",
+			args:  []any{"law%", "master%"},
+		},
+		{
+			name:  "in",
+			query: "id.in=1&id.in=2&author.notin=alice",
+			sql:   "WHERE author not in ($1) AND id in ($2, $3)",
+			args:  []any{"alice", 1, 2},
+		},
+		{
+			name:  "is",
+			query: "publish_year.isnot=null",
+			sql:   "WHERE publish_year is not null",
+		},
+		{
+			name:  "full-text search",
+			query: "title.fts=law&title.wfts=master",
+			sql:   "WHERE title @@ to_tsquery($1) AND title @@ websearch_to_tsquery($2)",
+			args:  []any{"law", "master"},
+		},
+		{
+			name:  "array",
+			query: "tags.cs=go&tags.cs=sql&tags.adj=x",
+			sql:   "WHERE tags -|- array[$1] AND tags @> array[$2, $3]",
+			args:  []any{"x", "go", "sql"},
+		},
+		{
+			name:  "and or",
+			query: "and=id.gt:13&and=id.lt:30&or=and.(title.ilike:alice%25,title.notilike:bob%25)&or=id.eq:1",
+			sql:   "WHERE id > $1 AND id < $2 OR id = $3 OR (title ilike $4 AND title not ilike $5)",
+			args:  []any{13, 30, 1, "alice%", "bob%"},
+		},
+		{
+			name:  "nested",
+			query: "or=and.(publish_year.isnot:null,or.(id.eq:1,id.gt:2))",
+			sql:   "WHERE (publish_year is not null AND (id = $1 OR id > $2))",
+			args:  []any{1, 2},
+		},
+		{
+			name:  "negated",
+			query: "id.not.gt=10&and=not.or.(id.eq:1,title.like:law%25)&or=not.and.()",
+			sql:   "WHERE NOT (id > $1) AND NOT (id = $2 OR title like $3) OR NOT (1 = 1)",
+			args:  []any{10, 1, "law%"},
+		},
+		{
+			name:  "between",
+			query: "id.between=10,20&id.notbetween=[2000,2010)&or=id.between:[1,)",
+			sql:   "WHERE id between $1 and $2 AND not (id >= $3 and id < $4) OR id >= $5",
+			args:  []any{10, 20, 2000, 2010, 1},
+		},
+		{
+			name:  "between in group",
+			query: "or=and.(id.between:(1,5],title.eq:law)",
+			sql:   "WHERE ((id > $1 and id <= $2) AND title = $3)",
+			args:  []any{1, 5, "law"},
+		},
+		{
+			name:  "quantified",
+			query: "tags.anyeq=go&tags.allneq=sql&tags.anylike=go%25&tags.allnotilike=x%25&or=tags.not.anygte:a",
+			sql:   "WHERE $1 = any(tags) AND exists (select 1 from unnest(tags) as e(v) where v like $2) AND $3 <> all(tags) AND not exists (select 1 from unnest(tags) as e(v) where not (v not ilike $4)) OR NOT ($5 <= any(tags))",
+			args:  []any{"go", "go%", "sql", "x%", "a"},
+		},
+		{
+			name:  "len",
+			query: "authors.len.gt=1&or=authors.len.not.in:0",
+			sql:   "WHERE coalesce(cardinality(authors), 0) > $1 OR NOT (coalesce(cardinality(authors), 0) in ($2))",
+			args:  []any{1, 0},
+		},
+		{
+			name:  "sort limit offset",
+			query: "sort_by=publish_year.desc.nullslast&limit=10&offset=20",
+			sql:   "ORDER BY publish_year DESC NULLS LAST LIMIT $1 OFFSET $2",
+			args:  []any{10, 20},
+		},
+	}
+
+	dec := goql.NewDecoder[Book]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := dec.Decode(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, args, err := goql.BuildSQL(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff(tt.args, args); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildSQLBadIsValue(t *testing.T) {
+	f := &goql.Filter{
+		And: []goql.FieldSet{
+			{Name: "married", Op: goql.OpIs, Values: []string{"1 or 1=1"}},
+		},
+	}
+
+	_, _, err := goql.BuildSQL(f)
+	if !errors.Is(err, goql.ErrBadValue) {
+		t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+	}
+}
+
+func TestBuildSQLJSON(t *testing.T) {
+	type Product struct {
+		Attrs json.RawMessage `q:"attrs,paths:size=int;dims.width=float64"`
+		Stock map[string]int
+		Meta  map[string]any
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "path",
+			query: "attrs.color.eq=red&attrs->dims->width.gt=1.5&attrs.size.between=10,20",
+			sql:   `WHERE (attrs->'dims'->>'width')::numeric > $1 AND attrs->>'color' = $2 AND (attrs->>'size')::numeric between $3 and $4`,
+			args:  []any{1.5, "red", 10, 20},
+		},
+		{
+			name:  "map path",
+			query: "stock.kl.gte=10&meta.color.like=r%25",
+			sql:   `WHERE meta->>'color' like $1 AND (stock->>'kl')::numeric >= $2`,
+			args:  []any{"r%", 10},
+		},
+		{
+			name:  "keys",
+			query: "attrs.haskey=color&stock.hasanykeys=kl&stock.hasanykeys=sg&meta.hasallkeys=a",
+			sql:   `WHERE attrs ? $1 AND meta ?& array[$2] AND stock ?| array[$3, $4]`,
+			args:  []any{"color", "a", "kl", "sg"},
+		},
+		{
+			name:  "containment",
+			query: `attrs.cs={"color":"red"}&or=meta.not.cd:{"a":1}`,
+			sql:   `WHERE attrs @> $1 OR NOT (meta <@ $2)`,
+			args:  []any{json.RawMessage(`{"color":"red"}`), json.RawMessage(`{"a":1}`)},
+		},
+	}
+
+	dec := goql.NewDecoder[Product]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, args, err := goql.BuildSQL(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff(tt.args, args); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			key   string
+			value string
+			err   error
+		}{
+			{"attrs.eq", "1", goql.ErrUnknownOperator},
+			{"attrs.color.haskey", "a", goql.ErrUnknownOperator},
+			{"attrs.size.eq", "small", goql.ErrBadValue},
+			{"attrs.co'lor.eq", "red", goql.ErrUnknownField},
+			{"attrs.cs", "not json", goql.ErrBadValue},
+		}
+
+		for _, tt := range tests {
+			_, err := dec.Decode(url.Values{tt.key: {tt.value}})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("%s: expected %v, got %v", tt.key, tt.err, err)
+			}
+		}
+	})
+
+	t.Run("tag ops", func(t *testing.T) {
+		type Product struct {
+			Attrs json.RawMessage `q:"attrs,ops:haskey,eq"`
+		}
+
+		dec := goql.NewDecoder[Product]()
+		if _, err := dec.DecodeString("attrs.color.eq=red&attrs.haskey=color"); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, key := range []string{"attrs.color.like", "attrs.not.haskey", "attrs.color.not.eq"} {
+			_, err := dec.Decode(url.Values{key: {"red"}})
+			if !errors.Is(err, goql.ErrUnknownOperator) {
+				t.Fatalf("%s: expected %v, got %v", key, goql.ErrUnknownOperator, err)
+			}
+		}
+	})
+}
+
+func TestBuildSQLInet(t *testing.T) {
+	type Host struct {
+		IP      netip.Addr
+		Network string `q:"network,type:cidr"`
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		args  []any
+	}{
+		{"contained by", "ip.cd=10.0.0.0/8", `WHERE ip <<= $1`, []any{"10.0.0.0/8"}},
+		{"contains", "network.cs=10.1.0.0/16", `WHERE network >>= $1`, []any{"10.1.0.0/16"}},
+		{"strictly contained by", "ip.sl=10.0.0.0/8", `WHERE ip << $1`, []any{"10.0.0.0/8"}},
+		{"eq", "ip.eq=10.0.0.1", `WHERE ip = $1`, []any{netip.MustParseAddr("10.0.0.1")}},
+		{"overlaps", "network.ov=10.0.0.0/8", `WHERE network && $1`, []any{"10.0.0.0/8"}},
+	}
+
+	dec := goql.NewDecoder[Host]()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, args, err := goql.BuildSQL(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff(tt.args, args, cmp.Comparer(func(a, b netip.Addr) bool { return a == b })); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDecoderInetDialect(t *testing.T) {
+	type Host struct {
+		IP      netip.Addr
+		Network string `q:"network,type:cidr"`
+	}
+
+	tests := []struct {
+		name    string
+		dialect goql.Dialect
+		query   string
+		err     error
+	}{
+		{"postgres", goql.Postgres{}, "ip.cd=10.0.0.0/8", nil},
+		{"mysql", goql.MySQL{}, "ip.cd=10.0.0.0/8", goql.ErrUnknownOperator},
+		{"sqlite", goql.SQLite{}, "network.ov=10.0.0.0/8", goql.ErrUnknownOperator},
+		{"mysql eq", goql.MySQL{}, "ip.eq=10.0.0.1", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := goql.NewDecoder[Host]().SetDialect(tt.dialect)
+
+			_, err := dec.DecodeString(tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
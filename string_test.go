@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestUnquote(t *testing.T) {
@@ -33,3 +34,28 @@ func TestUnquote(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitOutsideBrackets(t *testing.T) {
+	tests := []struct {
+		str string
+		exp []string
+	}{
+		{"age.gt:10,age.lt:20", []string{"age.gt:10", "age.lt:20"}},
+		{"age.between:[10,20),age.eq:5", []string{"age.between:[10,20)", "age.eq:5"}},
+		{"age.between:(10,20],age.eq:5", []string{"age.between:(10,20]", "age.eq:5"}},
+		{"name.like:a[b,name.like:c]d,age.eq:5", []string{"name.like:a[b", "name.like:c]d", "age.eq:5"}},
+		{"or.(age.between:[1,2),name.like:a[b),age.eq:5", []string{"or.(age.between:[1,2),name.like:a[b)", "age.eq:5"}},
+		{`name.eq:"a, b",age.eq:5`, []string{`name.eq:"a, b"`, "age.eq:5"}},
+		{"name.eq:[abc,name.eq:b", []string{"name.eq:[abc", "name.eq:b"}},
+		{"name.eq:(abc,name.eq:b)", []string{"name.eq:(abc,name.eq:b)"}},
+		{"age.notbetween:[10,20),age.not.between:(1,2]", []string{"age.notbetween:[10,20)", "age.not.between:(1,2]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if diff := cmp.Diff(tt.exp, goql.SplitOutsideBrackets(tt.str)); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+}
@@ -143,11 +143,17 @@ func Split3(str, by string) (string, string, string) {
 	return a, c, d
 }
 
+// SplitOutsideBrackets splits the values by comma, except those in the quotes,
+// the parentheses, or the range literals, e.g. `age.between:[10,20)`. The
+// range literals are only the values of the `between` and `notbetween` ops,
+// so that the brackets in the other values, e.g. `name.like:a[b`, are not
+// matched.
 func SplitOutsideBrackets(val string) []string {
 	result := make([]string, 0, 8)
 	r := []rune(val)
 	var s, b int
 	var q int
+	var rng bool
 
 	for i := 0; i < len(r); i++ {
 		switch r[i] {
@@ -157,12 +163,28 @@ func SplitOutsideBrackets(val string) []string {
 			} else {
 				q++
 			}
-		case '(':
-			b++
-		case ')':
-			b--
+		case '(', '[':
+			if q == 0 && !rng && isRangeValue(r[:i]) {
+				rng = true
+
+				continue
+			}
+
+			if r[i] == '(' {
+				b++
+			}
+		case ')', ']':
+			if rng {
+				rng = false
+
+				continue
+			}
+
+			if r[i] == ')' {
+				b--
+			}
 		case ',':
-			if b != 0 || q != 0 {
+			if b != 0 || q != 0 || rng {
 				continue
 			}
 
@@ -188,6 +210,14 @@ func SplitOutsideBrackets(val string) []string {
 	return result
 }
 
+// isRangeValue returns true if the value starts after the `between` or
+// `notbetween` op, e.g. `age.between:`.
+func isRangeValue(r []rune) bool {
+	s := string(r)
+
+	return strings.HasSuffix(s, "."+OpBetween.String()+":") || strings.HasSuffix(s, "."+OpNotBetween.String()+":")
+}
+
 func LowerCommonInitialism(field string) string {
 	if field == "" {
 		return ""
@@ -1,6 +1,9 @@
 package goql
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -8,7 +11,7 @@ import (
 	"strings"
 )
 
-var tagRe = regexp.MustCompile(`(?P<name>^[\w-]*)(,(?P<null1>null))?(,type:(?P<array>\[\])?(?P<null2>\*)?(?P<type>\w+))?(,ops:(?P<ops>(\w+(,\w+)*)+))?$`)
+var tagRe = regexp.MustCompile(`(?P<name>^[\w-]*)(,(?P<null1>null))?(,type:(?P<array>\[\])?(?P<null2>\*)?(?P<type>\w+))?(,col:(?P<col>[\w."]+))?(,paths:(?P<paths>[\w.*]+=\*?[\w.]+(;[\w.*]+=\*?[\w.]+)*))?(,layout:(?P<layout>[^,;]+(;[^,;]+)*))?(,ops:(?P<ops>(\w+(,\w+)*)+))?$`)
 
 type Tag struct {
 	Type Type
@@ -16,26 +19,89 @@ type Tag struct {
 	Tag  string
 	Sort bool
 	Ops  Op
+
+	// PK is true if the field is the unique tie-breaker of the sort, e.g.
+	// `sort:"true,pk"`.
+	PK bool
+
+	// Column is the SQL column, which defaults to the Name. It may be table
+	// qualified or quoted, e.g. `q:"publish_year,col:b.published_year"`.
+	Column string
+
+	// Index is the index of the struct field, see reflect.Value.FieldByIndex.
+	Index []int
+
+	// Paths are the types of the JSON paths, which are used to parse the
+	// values, e.g. `q:"attrs,paths:size=int;dims.width=float64"`. The
+	// wildcard `*` matches any path. Paths of map fields defaults to the
+	// type of the map's values.
+	Paths map[string]Type
+
+	// Layouts are the layouts of the time values, which overrides the
+	// TimeFormat of the decoder, e.g. `q:"dob,layout:2006-01-02;02/01/2006"`.
+	Layouts []string
+}
+
+// PathType returns the type of the JSON path. The path is compared as string
+// if the type is not specified.
+func (t *Tag) PathType(path []string) Type {
+	if typ, ok := t.Paths[strings.Join(path, ".")]; ok {
+		return typ
+	}
+
+	if typ, ok := t.Paths["*"]; ok {
+		return typ
+	}
+
+	return Type{Name: "string"}
+}
+
+// clone returns a deep copy of the tag.
+func (t *Tag) clone() *Tag {
+	c := *t
+	c.Index = append([]int(nil), t.Index...)
+	c.Layouts = append([]string(nil), t.Layouts...)
+
+	if t.Paths != nil {
+		c.Paths = make(map[string]Type, len(t.Paths))
+		for path, typ := range t.Paths {
+			c.Paths[path] = typ
+		}
+	}
+
+	return &c
 }
 
-func match(re *regexp.Regexp, str string) map[string]string {
+// match returns the named groups of the regexp, or false if the string does
+// not match.
+func match(re *regexp.Regexp, str string) (map[string]string, bool) {
 	if str == "" {
-		return nil
+		return nil, true
 	}
 
 	match := re.FindStringSubmatch(str)
+	if match == nil {
+		return nil, false
+	}
+
 	m := make(map[string]string)
-	for i, name := range tagRe.SubexpNames() {
+	for i, name := range re.SubexpNames() {
 		if i != 0 && name != "" {
 			m[name] = match[i]
 		}
 	}
 
-	return m
+	return m, true
 }
 
+// ParseTag parses the filter tag. The options are in order, i.e. `null`,
+// `type:`, `col:`, `paths:`, `layout:` and `ops:`, and the tag fails with
+// ErrInvalidOption otherwise.
 func ParseTag(tag string) (*Tag, error) {
-	m := match(tagRe, tag)
+	m, ok := match(tagRe, tag)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidOption, tag)
+	}
 
 	var ops Op
 	for _, raw := range strings.Split(m["ops"], ",") {
@@ -61,11 +127,36 @@ func ParseTag(tag string) (*Tag, error) {
 		ops = NewOps(t)
 	}
 
+	var paths map[string]Type
+	for _, raw := range strings.Split(m["paths"], ";") {
+		if raw == "" {
+			continue
+		}
+
+		if paths == nil {
+			paths = make(map[string]Type)
+		}
+
+		path, name := Split2(raw, "=")
+		paths[path] = Type{
+			Name: name,
+			Null: strings.HasPrefix(name, "*"),
+		}
+	}
+
+	var layouts []string
+	if m["layout"] != "" {
+		layouts = strings.Split(m["layout"], ";")
+	}
+
 	return &Tag{
-		Name: m["name"],
-		Type: t,
-		Tag:  tag,
-		Ops:  ops,
+		Name:    m["name"],
+		Type:    t,
+		Tag:     tag,
+		Ops:     ops,
+		Column:  m["col"],
+		Paths:   paths,
+		Layouts: layouts,
 	}, nil
 }
 
@@ -74,17 +165,32 @@ func NewOps(t Type) Op {
 		return 0
 	}
 
-	// All types are comparable.
-	ops := OpsComparable
+	// JSON types are not comparable, but they have key-existence and
+	// containment operators. The values are compared through the JSON paths
+	// instead.
+	if t.JSON() {
+		ops := OpsJSON | OpNot
+		if t.Null {
+			ops |= OpsNull
+		}
+
+		return ops
+	}
+
+	// All types are comparable, and can be negated.
+	ops := OpsComparable | OpNot
 
 	// Null type have special operators.
 	if t.Null {
 		ops |= OpsNull
 	}
 
-	// Array type have special operators.
+	// Array type have special operators, and the elements can be
+	// quantified.
 	if t.Array {
-		ops |= OpsRange
+		ops |= OpsRange | OpsQuantifier
+	} else {
+		ops |= OpsBetween
 	}
 
 	switch t.Name {
@@ -99,11 +205,56 @@ func NewOps(t Type) Op {
 	return ops
 }
 
+// ParseStruct parses the struct fields to tags. Fields of embedded structs are
+// promoted the same way as `encoding/json`, and fields of named nested structs
+// are prefixed with the name of the struct field, e.g. `address.city`.
 func ParseStruct(unk any, filterTag, sortTag string) (map[string]*Tag, error) {
+	t := reflect.TypeOf(unk)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	fields, err := parseFields(t, filterTag, sortTag, "", "", nil, map[reflect.Type]bool{t: true})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]structField)
+	for _, f := range fields {
+		byName[f.tag.Name] = append(byName[f.tag.Name], f)
+	}
+
+	var pk *Tag
+
 	tagByField := make(map[string]*Tag)
+	for name, fields := range byName {
+		f, ok := dominantField(fields)
+		if !ok {
+			continue
+		}
+
+		if f.tag.PK && pk != nil {
+			return nil, fmt.Errorf("goql: multiple pk fields: %q and %q", pk.Name, f.tag.Name)
+		}
+
+		if f.tag.PK {
+			pk = f.tag
+		}
+
+		tagByField[name] = f.tag
+	}
+
+	return tagByField, nil
+}
+
+type structField struct {
+	tag    *Tag
+	depth  int
+	tagged bool
+}
 
-	v := reflect.Indirect(reflect.ValueOf(unk))
-	t := v.Type()
+func parseFields(t reflect.Type, filterTag, sortTag, prefix, colPrefix string, index []int, visited map[reflect.Type]bool) ([]structField, error) {
+	var res []structField
 
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
@@ -118,31 +269,171 @@ func ParseStruct(unk any, filterTag, sortTag string) (map[string]*Tag, error) {
 			return nil, err
 		}
 
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		if st := structType(f.Type); st != nil && !c.Type.Valid() && !visited[st] {
+			// The fields of embedded structs are promoted, unless the
+			// embedded struct is named by the tag.
+			nestedPrefix, nestedColPrefix := prefix, colPrefix
+			if !f.Anonymous || c.Name != "" {
+				name := c.Name
+				if name == "" {
+					name = LowerCommonInitialism(f.Name)
+				}
+
+				col := c.Column
+				if col == "" {
+					col = name
+				}
+
+				nestedPrefix = prefix + name + "."
+				nestedColPrefix = colPrefix + col + "."
+			}
+
+			visited[st] = true
+			fields, err := parseFields(st, filterTag, sortTag, nestedPrefix, nestedColPrefix, idx, visited)
+			delete(visited, st)
+			if err != nil {
+				return nil, err
+			}
+
+			for j := range fields {
+				fields[j].depth++
+			}
+
+			res = append(res, fields...)
+
+			continue
+		}
+
+		tagged := c.Name != ""
 		if c.Name == "" {
 			// TODO: Make this customizable.
 			c.Name = LowerCommonInitialism(f.Name)
 		}
 
-		sort, _ := strconv.ParseBool(f.Tag.Get(sortTag))
-		c.Sort = sort
+		if c.Column == "" {
+			c.Column = c.Name
+		}
+
+		c.Name = prefix + c.Name
+		c.Column = colPrefix + c.Column
+
+		c.Index = idx
+
+		c.Sort, c.PK = parseSortTag(f.Tag.Get(sortTag))
+
+		res = append(res, structField{tag: c, tagged: tagged})
 
 		// Infer type from the tag.
 		if c.Type.Valid() {
-			tagByField[c.Name] = c
 			continue
 		}
 
 		// Infer type from the struct field.
 		c.Type = TypeOf(f.Type)
 
+		// The paths of the map defaults to the type of the map's values.
+		if _, ok := c.Paths["*"]; c.Type.Map && !ok {
+			if elem := mapElem(f.Type); elem.Kind() != reflect.Interface {
+				if c.Paths == nil {
+					c.Paths = make(map[string]Type)
+				}
+
+				c.Paths["*"] = TypeOf(elem)
+			}
+		}
+
 		// Tags does not specify any operations - infer from the struct field's
 		// type instead.
 		if !c.Ops.Valid() {
 			c.Ops = NewOps(c.Type)
 		}
+	}
+
+	return res, nil
+}
+
+// parseSortTag parses the sort tag, e.g. `sort:"true,pk"`.
+func parseSortTag(tag string) (sort, pk bool) {
+	raw, opts := Split2(tag, ",")
+	sort, _ = strconv.ParseBool(raw)
 
-		tagByField[c.Name] = c
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "pk" {
+			pk = true
+		}
 	}
 
-	return tagByField, nil
+	return
+}
+
+// dominantField returns the field that is the least nested, or the only one
+// that is tagged. Otherwise the fields are ambiguous, and none is returned.
+func dominantField(fields []structField) (structField, bool) {
+	depth := fields[0].depth
+	for _, f := range fields {
+		if f.depth < depth {
+			depth = f.depth
+		}
+	}
+
+	var dominant []structField
+	for _, f := range fields {
+		if f.depth == depth {
+			dominant = append(dominant, f)
+		}
+	}
+
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+
+	var tagged []structField
+	for _, f := range dominant {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+
+	return structField{}, false
+}
+
+func mapElem(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Elem()
+}
+
+var (
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType          = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structType returns the struct type of the field, if the fields of the struct
+// should be parsed. Structs that are values, e.g. time.Time or sql.NullString,
+// are not parsed.
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if isValueType(t) || t.Implements(valuerType) {
+		return nil
+	}
+
+	return t
 }
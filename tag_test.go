@@ -45,7 +45,7 @@ func TestTag(t *testing.T) {
 				Type: goql.Type{
 					Name: "uuid",
 				},
-				Ops: goql.OpsComparable,
+				Ops: goql.OpsComparable | goql.OpsBetween | goql.OpNot,
 				Tag: "id,type:uuid",
 			},
 		},
@@ -58,10 +58,19 @@ func TestTag(t *testing.T) {
 					Name:  "uuid",
 					Array: true,
 				},
-				Ops: goql.OpsComparable | goql.OpsRange,
+				Ops: goql.OpsComparable | goql.OpsRange | goql.OpsQuantifier | goql.OpNot,
 				Tag: "id,type:[]uuid",
 			},
 		},
+		{
+			name: "field layout",
+			tag:  "dob,layout:2006-01-02;02/01/2006 15:04",
+			exp: goql.Tag{
+				Name:    "dob",
+				Layouts: []string{"2006-01-02", "02/01/2006 15:04"},
+				Tag:     "dob,layout:2006-01-02;02/01/2006 15:04",
+			},
+		},
 		{
 			name: "field type null alternative",
 			tag:  "birthday,type:*date",
@@ -71,10 +80,33 @@ func TestTag(t *testing.T) {
 					Name: "*date",
 					Null: true,
 				},
-				Ops: goql.OpsComparable | goql.OpsNull,
+				Ops: goql.OpsComparable | goql.OpsNull | goql.OpsBetween | goql.OpNot,
 				Tag: "birthday,type:*date",
 			},
 		},
+		{
+			name: "field column",
+			tag:  "publish_year,col:b.published_year,ops:eq",
+			exp: goql.Tag{
+				Name:   "publish_year",
+				Tag:    "publish_year,col:b.published_year,ops:eq",
+				Ops:    goql.OpEq,
+				Column: "b.published_year",
+			},
+		},
+		{
+			name: "field paths",
+			tag:  "attrs,paths:size=*int;dims.width=float64,ops:haskey,cs",
+			exp: goql.Tag{
+				Name: "attrs",
+				Tag:  "attrs,paths:size=*int;dims.width=float64,ops:haskey,cs",
+				Ops:  goql.OpHasKey | goql.OpCs,
+				Paths: map[string]goql.Type{
+					"size":       {Name: "*int", Null: true},
+					"dims.width": {Name: "float64"},
+				},
+			},
+		},
 		{
 			name: "field ops",
 			tag:  "name,ops:eq",
@@ -99,3 +131,43 @@ func TestTag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStructEmbedded(t *testing.T) {
+	type A struct {
+		Name  string
+		Email string
+		Phone string `q:"phone"`
+	}
+
+	type B struct {
+		Email string
+		Phone string
+	}
+
+	type User struct {
+		A
+		B
+		Name string
+	}
+
+	tags, err := goql.ParseStruct(User{}, "q", "sort")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string][]int)
+	for name, tag := range tags {
+		names[name] = tag.Index
+	}
+
+	// The shallowest field wins, and the tagged field wins at the same depth.
+	// Ambiguous fields, e.g. email, are dropped.
+	exp := map[string][]int{
+		"name":  {2},
+		"phone": {0, 2},
+	}
+
+	if diff := cmp.Diff(exp, names); diff != "" {
+		t.Fatalf("exp+, got-: %s", diff)
+	}
+}
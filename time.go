@@ -0,0 +1,222 @@
+package goql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts of the time values, when none is
+// specified. The values without the time zone are in the TimeFormat.Location.
+var DefaultTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	dateLayout,
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+
+	epochRe = regexp.MustCompile(`^-?\d+$`)
+
+	// offsetRe matches the zone offset which `+` is decoded as space in the
+	// query string, e.g. `2006-01-02T15:04:05 08:00`.
+	offsetRe = regexp.MustCompile(`^(.*\d) (\d{2}:?\d{2})$`)
+)
+
+// TimeFormat is the format of the time values, see SetTimeFormat.
+type TimeFormat struct {
+	// Layouts are tried in order, and defaults to DefaultTimeLayouts. RFC3339
+	// is always tried last. The layouts of the field overrides them, e.g.
+	// `q:",layout:2006-01-02"`.
+	Layouts []string
+
+	// Location is the time zone of the values without one, and defaults to
+	// UTC.
+	Location *time.Location
+
+	// Epoch is the unit of the Unix epoch values, e.g. time.Second or
+	// time.Millisecond. The epoch values are not parsed if zero.
+	Epoch time.Duration
+}
+
+// SetTimeFormat sets the format of the time.Time fields, including the
+// pointers and the arrays.
+func (d *Decoder[T]) SetTimeFormat(f TimeFormat) *Decoder[T] {
+	if err := d.setTimeFormat(f); err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func (c *config) setTimeFormat(f TimeFormat) error {
+	if f.Epoch < 0 {
+		return fmt.Errorf("%w: time epoch cannot be negative", ErrInvalidOption)
+	}
+
+	for _, layout := range f.Layouts {
+		if layout == "" {
+			return fmt.Errorf("%w: time layout cannot be empty", ErrInvalidOption)
+		}
+	}
+
+	f.Layouts = append([]string(nil), f.Layouts...)
+	c.timeFormat = f
+
+	return nil
+}
+
+// formatsTime returns true if the time.Time values are parsed with the
+// TimeFormat, i.e. no parser is registered for time.Time.
+func (c *config) formatsTime() bool {
+	if _, ok := c.typeParsers[timeType]; ok {
+		return false
+	}
+
+	fn, ok := c.parsers["time.Time"]
+
+	return ok && sameParser(fn, ParseTime)
+}
+
+// Parse parses the time value with the layouts, or the Unix epoch.
+func (f TimeFormat) Parse(in string) (any, error) {
+	t, _, err := f.parse(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DateOnly returns true if the value is parsed with a layout without the
+// clock, e.g. `2006-01-02`. The `eq` of the dates compares the whole day.
+func (f TimeFormat) DateOnly(in string) bool {
+	_, date, err := f.parse(in)
+
+	return err == nil && date
+}
+
+// parse parses the time value, and returns true if the value is a date, i.e.
+// it is parsed with a layout without the clock.
+func (f TimeFormat) parse(in string) (time.Time, bool, error) {
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// RFC3339 is always accepted, as the cursors and the encoded filters are
+	// formatted with it.
+	layouts := DefaultTimeLayouts
+	if len(f.Layouts) > 0 {
+		layouts = append(f.Layouts[:len(f.Layouts):len(f.Layouts)], time.RFC3339)
+	}
+
+	// The error of the first layout is returned, when none matches.
+	var first error
+	for _, s := range []string{in, offsetRe.ReplaceAllString(in, "$1+$2")} {
+		for _, layout := range layouts {
+			t, err := time.ParseInLocation(layout, s, loc)
+			if err == nil {
+				return t, !hasClock(layout), nil
+			}
+
+			if first == nil {
+				first = err
+			}
+		}
+	}
+
+	if f.Epoch > 0 && epochRe.MatchString(in) {
+		t, err := f.parseEpoch(in)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+
+		return t.In(loc), false, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("%w: %s", ErrBadValue, first)
+}
+
+func (f TimeFormat) parseEpoch(in string) (time.Time, error) {
+	n, err := strconv.ParseInt(in, base10, bitSize64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrBadValue, err)
+	}
+
+	switch f.Epoch {
+	case time.Second:
+		return time.Unix(n, 0), nil
+	case time.Millisecond:
+		return time.UnixMilli(n), nil
+	}
+
+	// The epoch in the other units is converted to nanoseconds, which may
+	// overflow.
+	if unit := int64(f.Epoch); n > math.MaxInt64/unit || n < math.MinInt64/unit {
+		return time.Time{}, fmt.Errorf("%w: epoch out of range: %s", ErrBadValue, in)
+	}
+
+	return time.Unix(0, n*int64(f.Epoch)), nil
+}
+
+// layoutTokens are the tokens of the time layouts, see time.Layout. The
+// longer tokens are matched first, e.g. `2006` before `2`, so that the digits
+// of the other tokens, e.g. the zone offset `-0700`, are not mistaken for the
+// clock.
+var layoutTokens = []string{
+	"2006", "_2006", "002",
+	"-07:00:00", "-070000", "-07:00", "-0700", "-07",
+	"Z07:00:00", "Z070000", "Z07:00", "Z0700", "Z07",
+	"15", "03", "04", "05", "06", "01", "02", "_2",
+	"3", "4", "5",
+}
+
+// clockTokens are the layout tokens of the hour, minute and second.
+var clockTokens = map[string]bool{
+	"15": true,
+	"03": true,
+	"04": true,
+	"05": true,
+	"3":  true,
+	"4":  true,
+	"5":  true,
+}
+
+// hasClock returns true if the layout has the hour, minute or second, e.g.
+// `15:04`.
+func hasClock(layout string) bool {
+	for i := 0; i < len(layout); {
+		n := 1
+		for _, tok := range layoutTokens {
+			if strings.HasPrefix(layout[i:], tok) {
+				if clockTokens[tok] {
+					return true
+				}
+
+				n = len(tok)
+
+				break
+			}
+		}
+
+		i += n
+	}
+
+	return false
+}
+
+// dateRange returns the whole day of the date, `[date, date+1)`.
+func dateRange(t time.Time) Range {
+	return Range{
+		Lower:    t,
+		Upper:    t.AddDate(0, 0, 1),
+		LowerInc: true,
+	}
+}
@@ -0,0 +1,214 @@
+package goql_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alextanhongpin/goql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTimeFormat(t *testing.T) {
+	sgt := time.FixedZone("SGT", 8*60*60)
+
+	tests := []struct {
+		name   string
+		format goql.TimeFormat
+		inp    string
+		exp    time.Time
+		date   bool
+	}{
+		{"rfc3339", goql.TimeFormat{}, "2022-01-02T10:30:00Z", time.Date(2022, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"rfc3339 nano", goql.TimeFormat{}, "2022-01-02T10:30:00.5Z", time.Date(2022, 1, 2, 10, 30, 0, 5e8, time.UTC), false},
+		{"decoded plus offset", goql.TimeFormat{}, "2022-01-02T10:30:00 08:00", time.Date(2022, 1, 2, 10, 30, 0, 0, sgt), false},
+		{"no zone", goql.TimeFormat{}, "2022-01-02T10:30:00", time.Date(2022, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"no zone in location", goql.TimeFormat{Location: sgt}, "2022-01-02 10:30:00", time.Date(2022, 1, 2, 10, 30, 0, 0, sgt), false},
+		{"zone in location", goql.TimeFormat{Location: sgt}, "2022-01-02T10:30:00Z", time.Date(2022, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"date", goql.TimeFormat{}, "2022-01-02", time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"date in location", goql.TimeFormat{Location: sgt}, "2022-01-02", time.Date(2022, 1, 2, 0, 0, 0, 0, sgt), true},
+		{"layouts", goql.TimeFormat{Layouts: []string{"02/01/2006"}}, "02/01/2022", time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"layouts rfc3339", goql.TimeFormat{Layouts: []string{"02/01/2006"}}, "2022-01-02T10:30:00Z", time.Date(2022, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"epoch seconds", goql.TimeFormat{Epoch: time.Second}, "1641119400", time.Date(2022, 1, 2, 10, 30, 0, 0, time.UTC), false},
+		{"epoch millis", goql.TimeFormat{Epoch: time.Millisecond}, "1641119400500", time.Date(2022, 1, 2, 10, 30, 0, 5e8, time.UTC), false},
+		{"epoch micros", goql.TimeFormat{Epoch: time.Microsecond}, "1641119400500000", time.Date(2022, 1, 2, 10, 30, 0, 5e8, time.UTC), false},
+		{"date with offset", goql.TimeFormat{Layouts: []string{"2006-01-02 -0700"}}, "2022-01-02 +0000", time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"date with zone", goql.TimeFormat{Layouts: []string{"2006-01-02Z07:00"}}, "2022-01-02Z", time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"12-hour clock", goql.TimeFormat{Layouts: []string{"2006-01-02 3PM"}}, "2022-01-02 10AM", time.Date(2022, 1, 2, 10, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.format.Parse(tt.inp)
+			if err != nil {
+				t.Fatalf("parser error: %s", err)
+			}
+
+			got, ok := res.(time.Time)
+			if !ok || !tt.exp.Equal(got) {
+				t.Fatalf("expected %v, got %v", tt.exp, res)
+			}
+
+			if _, gotOffset := got.Zone(); !ok || gotOffset != offset(tt.exp) {
+				t.Fatalf("expected offset %d, got %d", offset(tt.exp), gotOffset)
+			}
+
+			if exp, got := tt.date, tt.format.DateOnly(tt.inp); exp != got {
+				t.Fatalf("expected %t, got %t", exp, got)
+			}
+		})
+	}
+
+	for _, inp := range []string{"yesterday", "1641119400", "2022-13-01"} {
+		t.Run("invalid "+inp, func(t *testing.T) {
+			_, err := goql.TimeFormat{}.Parse(inp)
+			if !errors.Is(err, goql.ErrBadValue) {
+				t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+			}
+		})
+	}
+
+	t.Run("epoch overflow", func(t *testing.T) {
+		_, err := goql.TimeFormat{Epoch: time.Microsecond}.Parse("9223372036854775807")
+		if !errors.Is(err, goql.ErrBadValue) {
+			t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+		}
+	})
+}
+
+func offset(t time.Time) int {
+	_, off := t.Zone()
+
+	return off
+}
+
+func TestDecoderTimeFormat(t *testing.T) {
+	type User struct {
+		CreatedAt time.Time  `q:"created_at"`
+		DeletedAt *time.Time `q:"deleted_at"`
+		Birthday  time.Time  `q:"birthday,layout:02/01/2006"`
+	}
+
+	sgt := time.FixedZone("SGT", 8*60*60)
+	day := time.Date(2022, 1, 2, 0, 0, 0, 0, sgt)
+	at := time.Date(2022, 1, 2, 10, 30, 0, 0, sgt)
+
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		args  []any
+	}{
+		{"eq date", "created_at.eq=2022-01-02", `WHERE (created_at >= $1 and created_at < $2)`, []any{day, day.AddDate(0, 0, 1)}},
+		{"neq date", "created_at.neq=2022-01-02", `WHERE not (created_at >= $1 and created_at < $2)`, []any{day, day.AddDate(0, 0, 1)}},
+		{"gte date", "created_at.gte=2022-01-02", `WHERE created_at >= $1`, []any{day}},
+		{"eq time", "created_at.eq=2022-01-02T10:30:00", `WHERE created_at = $1`, []any{at}},
+		{"eq epoch", "created_at.eq=1641090600", `WHERE created_at = $1`, []any{at}},
+		{"eq pointer date", "deleted_at.eq=2022-01-02", `WHERE (deleted_at >= $1 and deleted_at < $2)`, []any{day, day.AddDate(0, 0, 1)}},
+		{"field layout", "birthday.eq=02/01/2022", `WHERE (birthday >= $1 and birthday < $2)`, []any{day, day.AddDate(0, 0, 1)}},
+	}
+
+	dec := goql.NewDecoder[User]().SetTimeFormat(goql.TimeFormat{
+		Location: sgt,
+		Epoch:    time.Second,
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := dec.DecodeString(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sql, args, err := goql.BuildSQL(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if exp, got := tt.sql, sql; exp != got {
+				t.Fatalf("expected %q, got %q", exp, got)
+			}
+
+			if diff := cmp.Diff(tt.args, args, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+				t.Fatalf("exp+, got-: %s", diff)
+			}
+		})
+	}
+
+	t.Run("whole day", func(t *testing.T) {
+		f, err := dec.DecodeString("created_at.neq=2022-01-02")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fs := f.And[0]
+		if exp, got := goql.OpNotBetween, fs.Op; exp != got {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+
+		if diff := cmp.Diff([]string{"2022-01-02"}, fs.Values); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+
+		// The whole day is encoded as the range literal.
+		g, err := dec.Decode(goql.NewEncoder().Encode(f))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(fs.Value, g.And[0].Value, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+			t.Fatalf("exp+, got-: %s", diff)
+		}
+	})
+
+	t.Run("field layout only", func(t *testing.T) {
+		_, err := dec.DecodeString("birthday.eq=2022-01-02")
+		if !errors.Is(err, goql.ErrBadValue) {
+			t.Fatalf("expected %v, got %v", goql.ErrBadValue, err)
+		}
+	})
+
+	t.Run("invalid option", func(t *testing.T) {
+		_, err := goql.NewDecoderE[User](goql.WithTimeFormat(goql.TimeFormat{Epoch: -time.Second}))
+		if !errors.Is(err, goql.ErrInvalidOption) {
+			t.Fatalf("expected %v, got %v", goql.ErrInvalidOption, err)
+		}
+	})
+}
+
+func TestDecoderTimeParser(t *testing.T) {
+	type User struct {
+		CreatedAt time.Time `q:"created_at"`
+	}
+
+	at := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	parser := func(in string) (any, error) {
+		return at, nil
+	}
+
+	tests := []struct {
+		name string
+		dec  *goql.Decoder[User]
+	}{
+		{"parser before time format", goql.NewDecoder[User]().SetParser("time.Time", parser).SetTimeFormat(goql.TimeFormat{Epoch: time.Second})},
+		{"parser after time format", goql.NewDecoder[User]().SetTimeFormat(goql.TimeFormat{Epoch: time.Second}).SetParser("time.Time", parser)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.dec.DecodeString("created_at.eq=yesterday")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fs := f.And[0]
+			if exp, got := goql.OpEq, fs.Op; exp != got {
+				t.Fatalf("expected %v, got %v", exp, got)
+			}
+
+			if got, ok := fs.Value.(time.Time); !ok || !at.Equal(got) {
+				t.Fatalf("expected %v, got %v", at, fs.Value)
+			}
+		})
+	}
+}
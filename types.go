@@ -1,14 +1,26 @@
 package goql
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 )
 
+// jsonType is the name of the JSON type, which is also used to parse the
+// values of map fields.
+const jsonType = "json.RawMessage"
+
 // Type represents the type of the field.
 type Type struct {
 	Name  string
 	Null  bool
 	Array bool
+	Map   bool
+
+	// Kind is the kind of the underlying type, after the pointers and the
+	// arrays are unwrapped, e.g. reflect.String for `[]*Status`. The parser of
+	// the kind is used when there are no parsers for the type.
+	Kind reflect.Kind
 }
 
 // Valid returns true if the type is not empty.
@@ -16,6 +28,23 @@ func (t *Type) Valid() bool {
 	return t.Name != ""
 }
 
+// JSON returns true if the type is stored as a JSON document, which is either
+// a json.RawMessage or a map.
+func (t *Type) JSON() bool {
+	return t.Map || strings.TrimPrefix(t.Name, "*") == jsonType
+}
+
+// Inet returns true if the type is an IP address or a network, which has the
+// containment ops, e.g. `inet <<= cidr`.
+func (t *Type) Inet() bool {
+	switch strings.TrimPrefix(t.Name, "*") {
+	case "netip.Addr", "netip.Prefix", "net.IP", "net.IPNet", "inet", "cidr":
+		return true
+	default:
+		return false
+	}
+}
+
 /*
 TypeOf handles conversion for the following:
 
@@ -30,7 +59,7 @@ type Struct {
 */
 func TypeOf(t reflect.Type) Type {
 	res := Type{
-		Name: t.String(),
+		Name: typeName(t),
 	}
 
 	switch t.Kind() {
@@ -44,7 +73,15 @@ func TypeOf(t reflect.Type) Type {
 		t = t.Elem()
 		res.Null = true
 
+	case reflect.Map:
+		res.Map = true
+
 	case reflect.Slice, reflect.Array:
+		// Values such as uuid.UUID or net.IP are not arrays.
+		if isValueType(t) {
+			break
+		}
+
 		t = t.Elem()
 		res.Array = true
 
@@ -55,5 +92,33 @@ func TypeOf(t reflect.Type) Type {
 		}
 	}
 
+	res.Kind = t.Kind()
+
 	return res
 }
+
+// elemType returns the type of the array elements.
+func elemType(t Type) Type {
+	name := strings.TrimPrefix(t.Name, "[]")
+
+	return Type{
+		Name: name,
+		Null: strings.HasPrefix(name, "*"),
+		Kind: t.Kind,
+	}
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// typeName returns the name of the type. json.RawMessage may be an alias,
+// e.g. of jsontext.Value, so the name is kept stable for the parsers.
+func typeName(t reflect.Type) string {
+	switch t {
+	case rawMessageType:
+		return jsonType
+	case reflect.PointerTo(rawMessageType):
+		return "*" + jsonType
+	default:
+		return t.String()
+	}
+}
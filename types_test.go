@@ -55,6 +55,10 @@ type types struct {
 
 	uuid  uuid.UUID
 	uuidp *uuid.UUID
+
+	// Map
+	m  map[string]any
+	mp *map[string]int
 }
 
 func TestTypes(t *testing.T) {
@@ -66,33 +70,35 @@ func TestTypes(t *testing.T) {
 		typ any
 		exp goql.Type
 	}{
-		{typ.s, goql.Type{"string", false, false}},
-		{typ.sp, goql.Type{"*string", null, false}},
-		{typ.sn, goql.Type{"sql.NullString", false, false}},
-		{typ.i, goql.Type{"int", false, false}},
-		{typ.ip, goql.Type{"*int", null, false}},
-		{typ.in, goql.Type{"sql.NullInt64", false, false}},
-		{typ.f, goql.Type{"float64", false, false}},
-		{typ.fp, goql.Type{"*float64", null, false}},
-		{typ.fn, goql.Type{"sql.NullFloat64", false, false}},
-		{typ.b, goql.Type{"bool", false, false}},
-		{typ.bp, goql.Type{"*bool", null, false}},
-		{typ.bn, goql.Type{"sql.NullBool", false, false}},
-		{typ.t, goql.Type{"time.Time", false, false}},
-		{typ.tp, goql.Type{"*time.Time", null, false}},
-		{typ.tn, goql.Type{"sql.NullTime", false, false}},
-		{typ.nip, goql.Type{"net.IP", false, array}},
-		{typ.nipp, goql.Type{"*net.IP", null, array}},
-		{typ.nipn, goql.Type{"net.IPNet", false, false}},
-		{typ.nipnp, goql.Type{"*net.IPNet", null, false}},
-		{typ.jr, goql.Type{"json.RawMessage", false, array}},
-		{typ.js, goql.Type{"[]string", false, array}},
-		{typ.ji, goql.Type{"[]int", false, array}},
-		{typ.jf, goql.Type{"[]float64", false, array}},
-		{typ.jb, goql.Type{"[]bool", false, array}},
-		{typ.jt, goql.Type{"[]time.Time", false, array}},
-		{typ.uuid, goql.Type{"uuid.UUID", false, array}},
-		{typ.uuidp, goql.Type{"*uuid.UUID", null, array}},
+		{typ.s, goql.Type{"string", false, false, false, reflect.String}},
+		{typ.sp, goql.Type{"*string", null, false, false, reflect.String}},
+		{typ.sn, goql.Type{"sql.NullString", false, false, false, reflect.Struct}},
+		{typ.i, goql.Type{"int", false, false, false, reflect.Int}},
+		{typ.ip, goql.Type{"*int", null, false, false, reflect.Int}},
+		{typ.in, goql.Type{"sql.NullInt64", false, false, false, reflect.Struct}},
+		{typ.f, goql.Type{"float64", false, false, false, reflect.Float64}},
+		{typ.fp, goql.Type{"*float64", null, false, false, reflect.Float64}},
+		{typ.fn, goql.Type{"sql.NullFloat64", false, false, false, reflect.Struct}},
+		{typ.b, goql.Type{"bool", false, false, false, reflect.Bool}},
+		{typ.bp, goql.Type{"*bool", null, false, false, reflect.Bool}},
+		{typ.bn, goql.Type{"sql.NullBool", false, false, false, reflect.Struct}},
+		{typ.t, goql.Type{"time.Time", false, false, false, reflect.Struct}},
+		{typ.tp, goql.Type{"*time.Time", null, false, false, reflect.Struct}},
+		{typ.tn, goql.Type{"sql.NullTime", false, false, false, reflect.Struct}},
+		{typ.nip, goql.Type{"net.IP", false, false, false, reflect.Slice}},
+		{typ.nipp, goql.Type{"*net.IP", null, false, false, reflect.Slice}},
+		{typ.nipn, goql.Type{"net.IPNet", false, false, false, reflect.Struct}},
+		{typ.nipnp, goql.Type{"*net.IPNet", null, false, false, reflect.Struct}},
+		{typ.jr, goql.Type{"json.RawMessage", false, array, false, reflect.Uint8}},
+		{typ.js, goql.Type{"[]string", false, array, false, reflect.String}},
+		{typ.ji, goql.Type{"[]int", false, array, false, reflect.Int}},
+		{typ.jf, goql.Type{"[]float64", false, array, false, reflect.Float64}},
+		{typ.jb, goql.Type{"[]bool", false, array, false, reflect.Bool}},
+		{typ.jt, goql.Type{"[]time.Time", false, array, false, reflect.Struct}},
+		{typ.uuid, goql.Type{"uuid.UUID", false, false, false, reflect.Array}},
+		{typ.uuidp, goql.Type{"*uuid.UUID", null, false, false, reflect.Array}},
+		{typ.m, goql.Type{"map[string]interface {}", false, false, true, reflect.Map}},
+		{typ.mp, goql.Type{"*map[string]int", null, false, true, reflect.Map}},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,193 @@
+package goql
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// validator is implemented by the values that are validated after parsing,
+// e.g. value objects.
+type validator interface {
+	Validate() error
+}
+
+var validatorType = reflect.TypeOf((*validator)(nil)).Elem()
+
+// isValueType returns true if the type is parsed as a single value, e.g.
+// uuid.UUID or net.IP, even when it is an array.
+func isValueType(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+
+	return pt.Implements(textUnmarshalerType) || pt.Implements(scannerType)
+}
+
+// fieldTypes returns the Go types of the fields by the field name, and the
+// types of the map values by the field name with the `.*` suffix. Only the
+// types that are inferred from the struct fields are returned, since the
+// `type:` tags are aliases of the parsers.
+func fieldTypes(unk any, tags map[string]*Tag) map[string]reflect.Type {
+	t := reflect.TypeOf(unk)
+	if t == nil {
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	res := make(map[string]reflect.Type)
+	for name, tag := range tags {
+		if len(tag.Index) == 0 || tag.Type.Kind == reflect.Invalid {
+			continue
+		}
+
+		ft := t.FieldByIndex(tag.Index).Type
+		res[name] = baseType(ft)
+
+		if elem, ok := tag.Paths["*"]; ok && tag.Type.Map && elem.Kind != reflect.Invalid {
+			res[name+".*"] = baseType(mapElem(ft))
+		}
+	}
+
+	return res
+}
+
+// baseType returns the type after the pointers and the arrays are unwrapped,
+// the same as TypeOf.
+func baseType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		t = t.Elem()
+	case reflect.Slice, reflect.Array:
+		if isValueType(t) {
+			break
+		}
+
+		t = t.Elem()
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+
+	return t
+}
+
+// typeParser returns the parser of the named type, which is parsed in order
+// through:
+//  1. encoding.TextUnmarshaler, e.g. uuid.UUID or netip.Addr.
+//  2. json.Unmarshaler, e.g. decimals.
+//  3. sql.Scanner, which scans the string.
+//  4. the parser of the kind, which is converted to the type, e.g.
+//     `type Status string`.
+func typeParser(parsers map[string]ParserFn, t reflect.Type) (ParserFn, bool) {
+	pt := reflect.PointerTo(t)
+
+	switch {
+	case pt.Implements(textUnmarshalerType):
+		return func(in string) (any, error) {
+			v := reflect.New(t)
+			if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(in)); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+			}
+
+			return v.Elem().Interface(), nil
+		}, true
+	case pt.Implements(jsonUnmarshalerType):
+		return func(in string) (any, error) {
+			// The input may be a raw string, e.g. `1.5` or `hello`.
+			b := []byte(in)
+			if !json.Valid(b) {
+				b = []byte(strconv.Quote(in))
+			}
+
+			v := reflect.New(t)
+			if err := v.Interface().(json.Unmarshaler).UnmarshalJSON(b); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+			}
+
+			return v.Elem().Interface(), nil
+		}, true
+	case pt.Implements(scannerType):
+		return func(in string) (any, error) {
+			v := reflect.New(t)
+			if err := v.Interface().(sql.Scanner).Scan(in); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrBadValue, err)
+			}
+
+			return v.Elem().Interface(), nil
+		}, true
+	}
+
+	fn, ok := kindParser(parsers, t.Kind())
+	if !ok {
+		return nil, false
+	}
+
+	return func(in string) (any, error) {
+		v, err := fn(in)
+		if err != nil {
+			return nil, err
+		}
+
+		rv := reflect.ValueOf(v)
+		if !rv.Type().ConvertibleTo(t) {
+			return v, nil
+		}
+
+		return rv.Convert(t).Interface(), nil
+	}, true
+}
+
+// ParseValidate returns the parser that validates the parsed value, if the
+// value has the `Validate() error` method, on either the value or the pointer
+// receiver. The error wraps both ErrBadValue and the validation error.
+func ParseValidate(fn ParserFn) ParserFn {
+	return func(in string) (any, error) {
+		v, err := fn(in)
+		if err != nil {
+			return nil, err
+		}
+
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return v, nil
+		}
+
+		if err := validate(v); err != nil {
+			return nil, &validationError{err: err}
+		}
+
+		return v, nil
+	}
+}
+
+// validate calls the `Validate() error` method of the value. The method of the
+// pointer receiver is called on a copy of the value.
+func validate(v any) error {
+	if val, ok := v.(validator); ok {
+		return val.Validate()
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !reflect.PointerTo(rv.Type()).Implements(validatorType) {
+		return nil
+	}
+
+	ptr := reflect.New(rv.Type())
+	ptr.Elem().Set(rv)
+
+	return ptr.Interface().(validator).Validate()
+}